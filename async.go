@@ -0,0 +1,186 @@
+package tracer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// asyncMaxBatch is the maximum number of entries written with a single write
+const asyncMaxBatch = 256
+
+// asyncItem is an entry queued for the background writer,
+// or a flush request when entry is nil
+type asyncItem struct {
+	entry   *Entry
+	flushed chan struct{}
+}
+
+// asyncWriter queues entries for a background goroutine that batches them,
+// writes them and rotates the log file
+type asyncWriter struct {
+	queue      chan asyncItem
+	dropOnFull bool
+	dropped    *atomic.Uint64
+	mutex      sync.RWMutex
+	closed     bool
+	done       chan struct{}
+}
+
+// startAsync starts the background writer of the core
+func (c *core) startAsync(bufferSize int, dropOnFull bool) *asyncWriter {
+	w := &asyncWriter{
+		queue:      make(chan asyncItem, bufferSize),
+		dropOnFull: dropOnFull,
+		dropped:    &c.dropped,
+		done:       make(chan struct{}),
+	}
+	go c.runAsync(w)
+	return w
+}
+
+// runAsync drains the queue, writing entries in batches until the queue is closed
+func (c *core) runAsync(w *asyncWriter) {
+	defer close(w.done)
+
+	batch := make([]*Entry, 0, asyncMaxBatch)
+	var flushes []chan struct{}
+
+	for item := range w.queue {
+		batch, flushes = appendItem(batch, flushes, item)
+
+	drain:
+		for len(batch) < asyncMaxBatch {
+			select {
+			case item, ok := <-w.queue:
+				if !ok {
+					break drain
+				}
+				batch, flushes = appendItem(batch, flushes, item)
+			default:
+				break drain
+			}
+		}
+
+		if len(batch) > 0 {
+			c.writeEntries(batch, c.currentConfig())
+		}
+
+		for _, flushed := range flushes {
+			close(flushed)
+		}
+		batch = batch[:0]
+		flushes = flushes[:0]
+	}
+}
+
+func appendItem(batch []*Entry, flushes []chan struct{}, item asyncItem) ([]*Entry, []chan struct{}) {
+	if item.entry != nil {
+		batch = append(batch, item.entry)
+	}
+	if item.flushed != nil {
+		flushes = append(flushes, item.flushed)
+	}
+	return batch, flushes
+}
+
+// enqueue queues an entry for the background writer.
+// It returns false when the writer is closed and the caller must write the entry itself.
+func (w *asyncWriter) enqueue(entry *Entry) bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return false
+	}
+
+	if w.dropOnFull {
+		select {
+		case w.queue <- asyncItem{entry: entry}:
+		default:
+			w.dropped.Add(1)
+		}
+		return true
+	}
+
+	w.queue <- asyncItem{entry: entry}
+	return true
+}
+
+// flush waits until every entry queued before the call has been written
+func (w *asyncWriter) flush() {
+	w.mutex.RLock()
+	if w.closed {
+		w.mutex.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	w.queue <- asyncItem{flushed: flushed}
+	w.mutex.RUnlock()
+
+	<-flushed
+}
+
+// close writes the queued entries and stops the background writer
+func (w *asyncWriter) close() {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mutex.Unlock()
+
+	<-w.done
+}
+
+// Flush waits until every entry queued in async mode has been written.
+// It returns immediately when the tracer is not in async mode.
+func (t *Tracer) Flush() {
+	t.mutex.Lock()
+	async := t.async
+	t.mutex.Unlock()
+
+	if async != nil {
+		async.flush()
+	}
+}
+
+// Close writes the queued entries, stops the async writer, the config watch and the
+// periodic retention pass, closes the log file and waits for the background
+// compression and cleanup of rotated files.
+// Entries traced after Close are written synchronously and reopen the log file.
+func (t *Tracer) Close() error {
+	t.mutex.Lock()
+	async := t.async
+	t.async = nil
+	t.config.Async = false
+	t.mutex.Unlock()
+
+	if async != nil {
+		async.close()
+	}
+	t.stopWatching()
+	t.stopSweep()
+	t.closeLogFile()
+	t.background.Wait()
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the async queue was full
+func (t *Tracer) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// Flush waits until every entry queued by the default tracer has been written
+func Flush() {
+	std.Flush()
+}
+
+// Close writes the entries queued by the default tracer and stops its async writer
+func Close() error {
+	return std.Close()
+}
+
+// Dropped returns the number of entries dropped by the default tracer
+func Dropped() uint64 {
+	return std.Dropped()
+}
@@ -0,0 +1,83 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAsyncFlush verifies that queued entries are written by Flush and Close
+func TestAsyncFlush(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestAsync", Async: true, BufferSize: 16})
+	folderName := "Trace TestAsync"
+	defer os.RemoveAll(folderName)
+
+	for i := 0; i < 100; i++ {
+		tr.Tracef("Async entry %d;", i)
+	}
+	tr.Flush()
+
+	content, err := os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if !strings.Contains(string(content), fmt.Sprintf("Async entry %d;", i)) {
+			t.Fatalf("Expected log file to contain entry %d after Flush", i)
+		}
+	}
+
+	tr.Trace("Last async entry")
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	tr.Trace("Entry after close")
+
+	content, err = os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !contains(string(content), "Last async entry") {
+		t.Error("Expected Close to write the queued entries")
+	}
+	if !contains(string(content), "Entry after close") {
+		t.Error("Expected entries after Close to be written synchronously")
+	}
+	if tr.Dropped() != 0 {
+		t.Errorf("Expected no dropped entries in blocking mode, got %d", tr.Dropped())
+	}
+}
+
+// TestAsyncDropOnFull verifies that entries are dropped and counted when the queue is full
+func TestAsyncDropOnFull(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestAsyncDrop", Async: true, BufferSize: 1, DropOnFull: true})
+	folderName := "Trace TestAsyncDrop"
+	defer os.RemoveAll(folderName)
+	defer tr.Close()
+
+	// Block the background writer so the queue fills up
+	tr.writeMutex.Lock()
+	for i := 0; i < 100; i++ {
+		tr.Tracef("Entry %d", i)
+	}
+	tr.writeMutex.Unlock()
+	tr.Flush()
+
+	if tr.Dropped() == 0 {
+		t.Error("Expected entries to be dropped when the queue is full")
+	}
+}
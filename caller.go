@@ -0,0 +1,80 @@
+package tracer
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller is the source location of the call that wrote an entry
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// String returns the location as "dir/file.go:line"
+func (c Caller) String() string {
+	if c.File == "" {
+		return ""
+	}
+	dir, file := filepath.Split(c.File)
+	return filepath.Base(dir) + "/" + file + ":" + strconv.Itoa(c.Line)
+}
+
+// ShortFunction returns the function name without its package path, e.g. "main.run"
+func (c Caller) ShortFunction() string {
+	return c.Function[strings.LastIndex(c.Function, "/")+1:]
+}
+
+// AddCallerSkip returns a child tracer that reports the caller skip more frames up
+// the stack, for helpers wrapping the tracer methods
+func (t *Tracer) AddCallerSkip(skip int) *Tracer {
+	child := *t
+	child.callerSkip += skip
+	return &child
+}
+
+// AddCallerSkip returns a child of the default tracer that reports the caller skip
+// more frames up the stack
+func AddCallerSkip(skip int) *Tracer {
+	return std.AddCallerSkip(skip)
+}
+
+// stdCaller is the default tracer as used by the package-level functions,
+// which add one frame between the caller and the tracer methods
+var stdCaller = std.AddCallerSkip(1)
+
+// caller returns the location of the call skip frames above the function calling
+// caller, as runtime.Caller does, plus the tracer's own caller skip. Runtime frames,
+// such as those between a panic and a deferred RecoverPanic, are passed over.
+// It returns the zero Caller unless IncludeCaller is set.
+func (t *Tracer) caller(skip int) Caller {
+	if !t.includeCaller.Load() {
+		return Caller{}
+	}
+
+	var pcs [16]uintptr
+	n := runtime.Callers(skip+2+t.callerSkip, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			return Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+		}
+		if !more {
+			return Caller{}
+		}
+	}
+}
+
+// callerAt returns the location of a program counter, such as slog.Record.PC.
+// It returns the zero Caller unless IncludeCaller is set.
+func (t *Tracer) callerAt(pc uintptr) Caller {
+	if pc == 0 || !t.includeCaller.Load() {
+		return Caller{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+}
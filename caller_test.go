@@ -0,0 +1,157 @@
+package tracer
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// callerTracer returns a tracer with IncludeCaller that records the caller of every entry
+func callerTracer(callers *[]Caller) *Tracer {
+	return New(Config{
+		IncludeCaller: true,
+		Sinks: []Sink{{Writer: io.Discard, Filter: func(e *Entry) bool {
+			*callers = append(*callers, e.Caller)
+			return true
+		}}},
+	})
+}
+
+// here returns the line of its caller
+func here() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+// traceHelper is a wrapper of the kind AddCallerSkip is meant for
+func traceHelper(tr *Tracer, msg string) {
+	tr.AddCallerSkip(1).Warn(msg)
+}
+
+func panicking(tr *Tracer) {
+	defer tr.RecoverPanic()
+	panic("boom")
+}
+
+// TestIncludeCaller verifies the caller of entries written through every API layer
+func TestIncludeCaller(t *testing.T) {
+	var callers []Caller
+	tr := callerTracer(&callers)
+
+	var lines []int
+	tr.Trace("Method")
+	lines = append(lines, here()-1)
+	tr.Errorf("Formatted %d", 1)
+	lines = append(lines, here()-1)
+	tr.Module("poller").With("id", 2).TraceFields("Child")
+	lines = append(lines, here()-1)
+	traceHelper(tr, "Through a helper")
+	lines = append(lines, here()-1)
+	tr.TraceCtx(WithUser(context.Background(), "alice"), "Context")
+	lines = append(lines, here()-1)
+	endScope := tr.Scope("Scope")
+	lines = append(lines, here()-1)
+	endScope() // the exit line reports where the scope started
+	lines = append(lines, lines[len(lines)-1])
+
+	restore := tr.RedirectStdLog()
+	log.Println("Std log")
+	lines = append(lines, here()-1)
+	restore()
+
+	slog.New(NewSlogHandler(&SlogHandlerOptions{Tracer: tr})).Info("Slog")
+	lines = append(lines, here()-1)
+
+	if len(callers) != len(lines) {
+		t.Fatalf("Expected %d entries, got %d", len(lines), len(callers))
+	}
+	for i, caller := range callers {
+		if caller.Line != lines[i] || caller.Function != "github.com/rphpires/tracer.TestIncludeCaller" {
+			t.Errorf("Entry %d: expected caller_test.go:%d in TestIncludeCaller, got %s %s", i, lines[i], caller, caller.Function)
+		}
+	}
+
+	callers = nil
+	panicking(tr)
+	for _, caller := range callers {
+		if caller.Function != "github.com/rphpires/tracer.panicking" {
+			t.Errorf("Expected the panicking function as the caller, got %s", caller.Function)
+		}
+	}
+}
+
+// TestIncludeCallerPackageFunctions verifies the caller of the package-level functions
+func TestIncludeCallerPackageFunctions(t *testing.T) {
+	var callers []Caller
+	saved := std.config
+	std.config = callerTracer(&callers).config
+	std.includeCaller.Store(true)
+	defer func() {
+		std.config = saved
+		std.includeCaller.Store(false)
+	}()
+
+	Info("Package")
+	line := here() - 1
+	TraceHTML("<b>Package</b>")
+	recovering := func() {
+		defer RecoverPanic()
+		panic("boom")
+	}
+	recovering()
+
+	if len(callers) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(callers))
+	}
+	if callers[0].Line != line || callers[1].Line != line+2 {
+		t.Errorf("Expected lines %d and %d, got %s and %s", line, line+2, callers[0], callers[1])
+	}
+	if callers[2].Function != "github.com/rphpires/tracer.TestIncludeCallerPackageFunctions.func2" {
+		t.Errorf("Expected the panicking function as the caller, got %s", callers[2].Function)
+	}
+}
+
+// TestCallerDisabled verifies that no caller is recorded by default
+func TestCallerDisabled(t *testing.T) {
+	var caller Caller
+	tr := New(Config{Sinks: []Sink{{Writer: io.Discard, Filter: func(e *Entry) bool {
+		caller = e.Caller
+		return true
+	}}}})
+
+	tr.Trace("No caller")
+	if caller.File != "" {
+		t.Errorf("Expected no caller, got %s", caller)
+	}
+}
+
+// TestCallerFiltered verifies that the caller is not looked up for entries dropped
+// by the level or module checks
+func TestCallerFiltered(t *testing.T) {
+	tr := New(Config{
+		IncludeCaller: true,
+		MinLevel:      LevelWarn,
+		Modules:       "poller=off",
+		Sinks:         []Sink{{Writer: io.Discard}},
+	})
+
+	looked := 0
+	caller := func() Caller {
+		looked++
+		return Caller{}
+	}
+	tr.log(time.Now(), LevelInfo, message{text: "Below MinLevel"}, "", nil, caller)
+	tr.Module("poller").log(time.Now(), LevelError, message{text: "Module off"}, "", nil, caller)
+	if looked != 0 {
+		t.Errorf("Expected no caller lookup for filtered entries, got %d", looked)
+	}
+
+	tr.log(time.Now(), LevelError, message{text: "Written"}, "", nil, caller)
+	if looked != 1 {
+		t.Errorf("Expected one caller lookup for a written entry, got %d", looked)
+	}
+}
@@ -0,0 +1,89 @@
+package tracer
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+// compressedExtension is appended to the name of compressed rotated files
+const compressedExtension = ".gz"
+
+// compressLogFile gzips a rotated file to <filename>.gz and removes the original.
+// The compressed file keeps the modification time, so retention keeps its order.
+func compressLogFile(filename string) error {
+	src, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary name so an interrupted compression never looks complete
+	tmpFilename := filename + compressedExtension + ".tmp"
+	dst, err := os.Create(tmpFilename)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpFilename)
+		return err
+	}
+
+	if err := os.Chtimes(tmpFilename, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(tmpFilename)
+		return err
+	}
+	if err := os.Rename(tmpFilename, filename+compressedExtension); err != nil {
+		os.Remove(tmpFilename)
+		return err
+	}
+
+	src.Close()
+	return os.Remove(filename)
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying file
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipReadCloser) Close() error {
+	r.Reader.Close()
+	return r.file.Close()
+}
+
+// OpenLog opens a log file for reading, transparently decompressing
+// rotated files that were compressed to .gz
+func OpenLog(filename string) (io.ReadCloser, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(filename, compressedExtension) {
+		return file, nil
+	}
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: zr, file: file}, nil
+}
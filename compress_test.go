@@ -0,0 +1,60 @@
+package tracer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompressRotatedFiles verifies that rotated files are compressed, counted and readable
+func TestCompressRotatedFiles(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	folderName := "Trace TestCompress"
+	defer os.RemoveAll(folderName)
+
+	tr := New(Config{ExecutableName: "TestCompress", Encoder: TextEncoder{}, MaxSize: 400, MaxFiles: 4, Compress: true, DisableStdout: true})
+	tr.Trace("First entry")
+	for i := 0; i < 20; i++ {
+		tr.Tracef("Entry %d", i)
+		// Let each compression finish so retention sees the .gz files
+		tr.background.Wait()
+	}
+	tr.Close()
+
+	plain, _ := filepath.Glob(filepath.Join(folderName, "*_trace.log"))
+	if len(plain) != 0 {
+		t.Errorf("Expected rotated files to be compressed, found %v", plain)
+	}
+
+	compressed, _ := filepath.Glob(filepath.Join(folderName, "*.log.gz"))
+	if len(compressed) == 0 {
+		t.Fatal("Expected compressed rotated files")
+	}
+
+	files, err := getLogFiles(folderName, "log")
+	if err != nil {
+		t.Fatalf("Failed to list log files: %v", err)
+	}
+	if len(files) > 4 {
+		t.Errorf("Expected retention to count compressed files, got %d files", len(files))
+	}
+
+	r, err := OpenLog(compressed[len(compressed)-1])
+	if err != nil {
+		t.Fatalf("Failed to open compressed log file: %v", err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read compressed log file: %v", err)
+	}
+	if !contains(string(content), "INFO  - Entry") {
+		t.Errorf("Expected decompressed entries, got '%s'", content)
+	}
+}
@@ -0,0 +1,99 @@
+package tracer
+
+import "context"
+
+// contextKey is the key of the tracer values stored in a context
+type contextKey struct{}
+
+// contextValues are the user ID, fields, goroutine name, scope depth and span
+// carried by a context
+type contextValues struct {
+	userID    string
+	fields    []Field
+	goroutine string
+	depth     int
+	span      SpanContext
+}
+
+func valuesFrom(ctx context.Context) contextValues {
+	if ctx == nil {
+		return contextValues{}
+	}
+	values, _ := ctx.Value(contextKey{}).(contextValues)
+	return values
+}
+
+// WithUser returns a context carrying the user ID written with the entries traced
+// with it, instead of the UserID of the tracer
+func WithUser(ctx context.Context, userID string) context.Context {
+	values := valuesFrom(ctx)
+	values.userID = userID
+	return context.WithValue(ctx, contextKey{}, values)
+}
+
+// WithFields returns a context carrying key/value fields, such as a request ID,
+// written with the entries traced with it. Fields add to those already carried.
+func WithFields(ctx context.Context, args ...any) context.Context {
+	values := valuesFrom(ctx)
+	values.fields = append(values.fields[:len(values.fields):len(values.fields)], fieldsFromArgs(args)...)
+	return context.WithValue(ctx, contextKey{}, values)
+}
+
+// WithGoroutineName returns a context carrying a logical name, such as "worker-3",
+// written with the entries traced with it like a name set with SetGoroutineName
+func WithGoroutineName(ctx context.Context, name string) context.Context {
+	values := valuesFrom(ctx)
+	values.goroutine = name
+	return context.WithValue(ctx, contextKey{}, values)
+}
+
+// WithContext returns a child tracer that writes the user ID, fields, goroutine
+// name and span carried by ctx with every entry. The UserID of the tracer remains
+// the fallback when ctx carries no user ID.
+func (t *Tracer) WithContext(ctx context.Context) *Tracer {
+	values := valuesFrom(ctx)
+	if values.userID == "" && len(values.fields) == 0 && values.goroutine == "" && values.depth == 0 && !values.span.IsValid() {
+		return t
+	}
+
+	child := *t
+	if values.userID != "" {
+		child.userID = values.userID
+	}
+	if values.goroutine != "" {
+		child.goroutine = values.goroutine
+	}
+	if values.span.IsValid() {
+		child.span = values.span
+	}
+	child.depth += values.depth
+	child.fields = append(t.fields[:len(t.fields):len(t.fields)], values.fields...)
+	return &child
+}
+
+// TraceCtx writes values to the trace log like Trace, with the user ID and fields of ctx
+func (t *Tracer) TraceCtx(ctx context.Context, a ...any) {
+	t.WithContext(ctx).traceWithColorInternal(LevelInfo, sprintMessage(a...), "")
+}
+
+// TracefCtx writes a formatted message to the trace log like Tracef, with the user ID
+// and fields of ctx
+func (t *Tracer) TracefCtx(ctx context.Context, format string, a ...any) {
+	t.WithContext(ctx).traceWithColorInternal(LevelInfo, sprintfMessage(format, a...), "")
+}
+
+// WithContext returns a child of the default tracer that writes the values of ctx
+func WithContext(ctx context.Context) *Tracer {
+	return std.WithContext(ctx)
+}
+
+// TraceCtx writes values to the default trace log with the user ID and fields of ctx
+func TraceCtx(ctx context.Context, a ...any) {
+	stdCaller.TraceCtx(ctx, a...)
+}
+
+// TracefCtx writes a formatted message to the default trace log with the user ID
+// and fields of ctx
+func TracefCtx(ctx context.Context, format string, a ...any) {
+	stdCaller.TracefCtx(ctx, format, a...)
+}
@@ -0,0 +1,76 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// TestTraceCtx verifies that the user ID and fields are taken from the context,
+// with the tracer UserID as the fallback
+func TestTraceCtx(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{UserID: "system", Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	ctx := WithUser(context.Background(), "alice")
+	ctx = WithFields(ctx, "request_id", "r-1")
+	ctx = WithFields(ctx, "path", "/orders")
+	tr.TraceCtx(ctx, "Order", 42)
+	tr.TracefCtx(WithFields(context.Background(), "request_id", "r-2"), "Order %d", 43)
+	tr.TraceCtx(context.Background(), "Background")
+
+	output := buf.String()
+	for _, expected := range []string{
+		"INFO  - alice - Order 42 request_id=r-1 path=/orders",
+		"INFO  - system - Order 43 request_id=r-2",
+		"INFO  - system - Background\n",
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected '%s', got:\n%s", expected, output)
+		}
+	}
+}
+
+// TestWithContext verifies that a context tracer writes its values at every level,
+// and that deriving contexts leaves the parent unchanged
+func TestWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{IncludeGoroutineName: true, Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	parent := WithFields(context.Background(), "request_id", "r-1")
+	child := WithGoroutineName(WithFields(parent, "step", 2), "handler")
+	tr.WithContext(child).Error("Failed")
+	tr.WithContext(parent).Warn("Slow")
+
+	output := buf.String()
+	if !contains(output, "ERROR - handler - ** Failed request_id=r-1 step=2") {
+		t.Errorf("Expected the child context values, got:\n%s", output)
+	}
+	if !contains(output, "WARN  - Slow request_id=r-1\n") {
+		t.Errorf("Expected the parent context values only, got:\n%s", output)
+	}
+	var plain bytes.Buffer
+	New(Config{Sinks: []Sink{{Writer: &plain, Encoder: TextEncoder{}}}}).WithContext(child).Error("Failed")
+	if !contains(plain.String(), "ERROR - ** Failed request_id=r-1 step=2") {
+		t.Errorf("Expected no goroutine name without the goroutine columns, got:\n%s", plain.String())
+	}
+	if tr.WithContext(context.Background()) != tr {
+		t.Error("Expected the tracer itself for a context without values")
+	}
+}
+
+// TestSlogContext verifies that the slog handler reads the context values
+func TestSlogContext(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+	logger := slog.New(NewSlogHandler(&SlogHandlerOptions{Tracer: tr}))
+
+	ctx := WithFields(WithUser(context.Background(), "bob"), "request_id", "r-3")
+	logger.InfoContext(ctx, "Login", "ok", true)
+
+	expected := "INFO  - bob - Login request_id=r-3 ok=true"
+	if !contains(buf.String(), expected) {
+		t.Errorf("Expected '%s', got '%s'", expected, buf.String())
+	}
+}
@@ -17,6 +17,8 @@
 //   - TraceIntegraEnable.txt
 //   - Trace.txt
 //
+// or set the TRACER_ENABLE environment variable to 1.
+//
 // Basic usage:
 //
 //	package main
@@ -52,6 +54,11 @@
 //	    MaxFiles:       15,
 //	})
 //
+// Independent tracers, each with its own folder and settings:
+//
+//	poller := tracer.New(tracer.Config{ExecutableName: "Poller"})
+//	poller.Tracef("Polling device %d", 2)
+//
 // Panic Recovery:
 //
 //	func riskyOperation() {
@@ -0,0 +1,184 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnableMode selects whether the log files are written
+type EnableMode uint8
+
+const (
+	// EnableAuto writes the log files when the TRACER_ENABLE environment variable
+	// says so or, when it is not set, while a marker file exists (default)
+	EnableAuto EnableMode = iota
+	// EnableOn always writes the log files
+	EnableOn
+	// EnableOff never writes the log files
+	EnableOff
+)
+
+// enableEnvVar is the environment variable that turns tracing on or off,
+// taking precedence over the marker files
+const enableEnvVar = "TRACER_ENABLE"
+
+// enableCheckInterval is how often the enable setting is looked up again by default
+const enableCheckInterval = time.Second
+
+// defaultEnableFiles are the marker files that enable tracing
+var defaultEnableFiles = []string{"TraceEnable.txt", "TraceIntegraEnable.txt", "Trace.txt"}
+
+// isTraceEnabled reports whether the log files are written, looking up the
+// environment variable and the marker files without caching
+func isTraceEnabled(cfg *Config) bool {
+	switch cfg.Enabled {
+	case EnableOn:
+		return true
+	case EnableOff:
+		return false
+	}
+
+	if enabled, ok := parseEnable(os.Getenv(enableEnvVar)); ok {
+		return enabled
+	}
+
+	name, _ := findEnableFile(cfg)
+	return name != ""
+}
+
+// findEnableFile returns the first marker file found, or an empty name
+func findEnableFile(cfg *Config) (string, os.FileInfo) {
+	files := cfg.EnableFiles
+	if len(files) == 0 {
+		files = defaultEnableFiles
+	}
+	dirs := cfg.EnableDirs
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	for _, dir := range dirs {
+		for _, file := range files {
+			name := filepath.Join(dir, file)
+			if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() {
+				return name, info
+			}
+		}
+	}
+	return "", nil
+}
+
+// parseEnable parses an on/off value such as "1", "true", "on" or "no".
+// It reports false for ok when the value is empty or not recognized.
+func parseEnable(value string) (enabled, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "on", "yes":
+		return true, true
+	case "0", "false", "off", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// enableState caches the enable check, and the settings read from the marker
+// file together with the modification time they were read at
+type enableState struct {
+	mutex     sync.Mutex
+	checkedAt time.Time
+	enabled   bool
+	file      string
+	modTime   time.Time
+	size      int64
+	settings  fileSettings
+}
+
+// refresh looks the enable setting and the marker file up again once the
+// EnableCheckInterval has passed. The marker file is only read again when its
+// name, modification time or size changed. The mutex must be held.
+func (s *enableState) refresh(cfg *Config) {
+	interval := cfg.EnableCheckInterval
+	if interval <= 0 {
+		interval = enableCheckInterval
+	}
+	if !s.checkedAt.IsZero() && time.Since(s.checkedAt) < interval {
+		return
+	}
+	s.checkedAt = time.Now()
+	s.enabled = isTraceEnabled(cfg)
+
+	name, info := findEnableFile(cfg)
+	if name == "" {
+		s.file, s.settings = "", fileSettings{}
+		return
+	}
+	if name == s.file && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return
+	}
+	s.file, s.modTime, s.size = name, info.ModTime(), info.Size()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Printf("Error reading trace settings: %v\n", err)
+		s.settings = fileSettings{}
+		return
+	}
+	settings, err := parseSettings(data)
+	if err != nil {
+		fmt.Printf("Error parsing trace settings in %s: %v\n", name, err)
+	}
+	s.settings = settings
+}
+
+// traceEnabled is isTraceEnabled cached for the EnableCheckInterval of the config
+func (c *core) traceEnabled(cfg *Config) bool {
+	if cfg.Enabled != EnableAuto {
+		return cfg.Enabled == EnableOn
+	}
+
+	c.enable.mutex.Lock()
+	defer c.enable.mutex.Unlock()
+	c.enable.refresh(cfg)
+	return c.enable.enabled
+}
+
+// currentConfig returns the configuration with the settings of the watched config
+// file and then of the marker file applied
+func (c *core) currentConfig() Config {
+	c.mutex.Lock()
+	cfg := c.config
+	if c.watch != nil {
+		c.watch.settings.apply(&cfg)
+	}
+	c.mutex.Unlock()
+
+	c.enable.mutex.Lock()
+	defer c.enable.mutex.Unlock()
+	c.enable.refresh(&cfg)
+	c.enable.settings.apply(&cfg)
+	return cfg
+}
+
+// refreshEnabled makes the next write look up the enable setting again
+func (c *core) refreshEnabled() {
+	c.enable.mutex.Lock()
+	defer c.enable.mutex.Unlock()
+	c.enable.checkedAt = time.Time{}
+}
+
+// SetEnabled forces the log files on or off, or with EnableAuto goes back to
+// the environment variable and the marker files. Unlike SetConfig it accepts EnableAuto.
+func (t *Tracer) SetEnabled(mode EnableMode) {
+	t.mutex.Lock()
+	t.config.Enabled = mode
+	t.mutex.Unlock()
+	t.refreshEnabled()
+}
+
+// SetEnabled forces the log files of the default tracer on or off, or back to auto
+func SetEnabled(mode EnableMode) {
+	std.SetEnabled(mode)
+}
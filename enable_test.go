@@ -0,0 +1,75 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEnableSources verifies the precedence of Config.Enabled, TRACER_ENABLE and the marker files
+func TestEnableSources(t *testing.T) {
+	os.Remove("TraceEnable.txt")
+	os.Remove("TraceIntegraEnable.txt")
+	os.Remove("Trace.txt")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "debug.flag"), []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create marker file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		env      string
+		cfg      Config
+		expected bool
+	}{
+		{"no marker", "", Config{}, false},
+		{"forced on", "", Config{Enabled: EnableOn}, true},
+		{"env on", "1", Config{}, true},
+		{"env off", "off", Config{EnableFiles: []string{"debug.flag"}, EnableDirs: []string{dir}}, false},
+		{"forced on over env", "false", Config{Enabled: EnableOn}, true},
+		{"forced off", "true", Config{Enabled: EnableOff}, false},
+		{"custom marker", "", Config{EnableFiles: []string{"debug.flag"}, EnableDirs: []string{".", dir}}, true},
+		{"unknown env value", "maybe", Config{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(enableEnvVar, tt.env)
+			if enabled := isTraceEnabled(&tt.cfg); enabled != tt.expected {
+				t.Errorf("Expected enabled=%v, got %v", tt.expected, enabled)
+			}
+		})
+	}
+}
+
+// TestEnableCheckCached verifies that the marker files are looked up once per interval
+func TestEnableCheckCached(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "TraceEnable.txt")
+
+	tr := New(Config{ExecutableName: "TestEnableCache", EnableDirs: []string{dir}, EnableCheckInterval: time.Hour})
+	cfg := tr.config
+
+	if tr.traceEnabled(&cfg) {
+		t.Fatal("Expected tracing to be disabled")
+	}
+	if err := os.WriteFile(marker, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create marker file: %v", err)
+	}
+	if tr.traceEnabled(&cfg) {
+		t.Error("Expected the cached result until the interval elapses")
+	}
+
+	tr.refreshEnabled()
+	if !tr.traceEnabled(&cfg) {
+		t.Error("Expected tracing to be enabled after a refresh")
+	}
+
+	tr.SetEnabled(EnableOff)
+	cfg = tr.config
+	if tr.traceEnabled(&cfg) {
+		t.Error("Expected tracing to be forced off")
+	}
+}
@@ -0,0 +1,251 @@
+package tracer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"time"
+)
+
+// Encoder renders trace entries in a log file format
+type Encoder interface {
+	// Extension returns the file extension of the format without the dot, e.g. "html".
+	// Log files are named trace.<ext>, and retention only counts files with this extension.
+	Extension() string
+	// Header returns the bytes written at the start of every new log file
+	Header() []byte
+	// Footer returns the bytes written at the end of a log file when it is rotated
+	Footer() []byte
+	// Encode appends the rendered entry to dst and returns the extended buffer
+	Encode(dst []byte, entry *Entry) []byte
+}
+
+// HTMLEncoder writes colored HTML with the interactive filter script.
+// It is the default encoder.
+type HTMLEncoder struct{}
+
+// Extension returns "html"
+func (HTMLEncoder) Extension() string {
+	return "html"
+}
+
+// Header returns the HTML page header with the filter script and styles
+func (HTMLEncoder) Header() []byte {
+	return []byte(htmlPageHeader)
+}
+
+// Footer closes the markup opened by the header and the entries
+func (HTMLEncoder) Footer() []byte {
+	return []byte(htmlPageFooter)
+}
+
+// Encode appends the entry as a line of colored HTML
+func (HTMLEncoder) Encode(dst []byte, entry *Entry) []byte {
+	dst = append(dst, "\n<br></font><font color=\""...)
+	dst = append(dst, entry.Color...)
+	dst = append(dst, "\">"...)
+	dst = entry.Time.AppendFormat(dst, "2006-01-02 15:04:05.000")
+	dst = append(dst, " - "...)
+	if columns := processColumns(entry); columns != "" {
+		dst = append(dst, html.EscapeString(columns)...)
+		dst = append(dst, " - "...)
+	}
+	if entry.UserID != "" {
+		dst = append(dst, html.EscapeString(entry.UserID)...)
+		dst = append(dst, " - "...)
+	}
+	if entry.Caller.File != "" {
+		dst = append(dst, `<span class="caller">`...)
+		dst = append(dst, html.EscapeString(entry.Caller.String()+" "+entry.Caller.ShortFunction())...)
+		dst = append(dst, "</span> - "...)
+	}
+	dst = appendIndent(dst, entry, "&nbsp;&nbsp;&nbsp;&nbsp;")
+	if entry.Module != "" {
+		dst = append(dst, '[')
+		dst = append(dst, html.EscapeString(entry.Module)...)
+		dst = append(dst, "] "...)
+	}
+	dst = append(dst, htmlMessage(entry)...)
+	dst = append(dst, formatHTMLFields(entry.Fields)...)
+	if entry.TraceID != "" {
+		// Clicking the IDs filters the page by the trace, and clicking again clears the filter
+		traceID := html.EscapeString(entry.TraceID)
+		dst = append(dst, ` <span class="trace" title="Filter by trace" onclick="filter_trace('`...)
+		dst = append(dst, traceID...)
+		dst = append(dst, `')">trace_id=`...)
+		dst = append(dst, traceID...)
+		dst = append(dst, " span_id="...)
+		dst = append(dst, html.EscapeString(entry.SpanID)...)
+		dst = append(dst, "</span>"...)
+	}
+	return dst
+}
+
+// JSONEncoder writes one JSON object per line (JSON Lines), for log shippers and jq
+type JSONEncoder struct{}
+
+// Extension returns "jsonl"
+func (JSONEncoder) Extension() string {
+	return "jsonl"
+}
+
+// Header returns nothing, JSON Lines files have no header
+func (JSONEncoder) Header() []byte {
+	return nil
+}
+
+// Footer returns nothing, JSON Lines files have no footer
+func (JSONEncoder) Footer() []byte {
+	return nil
+}
+
+// Encode appends the entry as a JSON object followed by a newline.
+// Fields are kept as a JSON object with their original value types.
+func (JSONEncoder) Encode(dst []byte, entry *Entry) []byte {
+	dst = append(dst, `{"time":`...)
+	dst = strconv.AppendQuote(dst, entry.Time.Format(time.RFC3339Nano))
+	dst = append(dst, `,"level":`...)
+	dst = strconv.AppendQuote(dst, entry.Level.String())
+	dst = append(dst, `,"color":`...)
+	dst = appendJSON(dst, entry.Color)
+	if entry.UserID != "" {
+		dst = append(dst, `,"user_id":`...)
+		dst = appendJSON(dst, entry.UserID)
+	}
+	if entry.PID != 0 {
+		dst = append(dst, `,"pid":`...)
+		dst = strconv.AppendInt(dst, int64(entry.PID), 10)
+	}
+	if entry.GoroutineID != 0 {
+		dst = append(dst, `,"goroutine_id":`...)
+		dst = strconv.AppendUint(dst, entry.GoroutineID, 10)
+	}
+	if entry.Goroutine != "" {
+		dst = append(dst, `,"goroutine":`...)
+		dst = appendJSON(dst, entry.Goroutine)
+	}
+	if entry.Caller.File != "" {
+		dst = append(dst, `,"caller":{"file":`...)
+		dst = appendJSON(dst, entry.Caller.File)
+		dst = append(dst, `,"line":`...)
+		dst = strconv.AppendInt(dst, int64(entry.Caller.Line), 10)
+		dst = append(dst, `,"function":`...)
+		dst = appendJSON(dst, entry.Caller.Function)
+		dst = append(dst, '}')
+	}
+	if entry.Module != "" {
+		dst = append(dst, `,"module":`...)
+		dst = appendJSON(dst, entry.Module)
+	}
+	if entry.Depth != 0 {
+		dst = append(dst, `,"depth":`...)
+		dst = strconv.AppendInt(dst, int64(entry.Depth), 10)
+	}
+	if entry.TraceID != "" {
+		dst = append(dst, `,"trace_id":`...)
+		dst = appendJSON(dst, entry.TraceID)
+		dst = append(dst, `,"span_id":`...)
+		dst = appendJSON(dst, entry.SpanID)
+	}
+	dst = append(dst, `,"message":`...)
+	dst = appendJSON(dst, entry.Text())
+	if len(entry.Fields) > 0 {
+		dst = append(dst, `,"fields":{`...)
+		for i, f := range entry.Fields {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendJSON(dst, f.Key)
+			dst = append(dst, ':')
+			dst = appendJSON(dst, f.Value)
+		}
+		dst = append(dst, '}')
+	}
+	dst = append(dst, "}\n"...)
+	return dst
+}
+
+// appendJSON appends the JSON encoding of v. Errors are written as their message,
+// and values that cannot be encoded are written as their fmt representation.
+func appendJSON(dst []byte, v any) []byte {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return append(dst, data...)
+}
+
+// TextEncoder writes plain text lines for grep and terminals
+type TextEncoder struct{}
+
+// Extension returns "log"
+func (TextEncoder) Extension() string {
+	return "log"
+}
+
+// Header returns nothing, text files have no header
+func (TextEncoder) Header() []byte {
+	return nil
+}
+
+// Footer returns nothing, text files have no footer
+func (TextEncoder) Footer() []byte {
+	return nil
+}
+
+// Encode appends the entry as
+// "<timestamp> <LEVEL> - <pid> - g<id> <name> - <user> - <caller> - [<module>] <message> key=value trace_id=<id> span_id=<id>"
+func (TextEncoder) Encode(dst []byte, entry *Entry) []byte {
+	dst = entry.Time.AppendFormat(dst, "2006-01-02 15:04:05.000")
+	dst = append(dst, ' ')
+	dst = append(dst, fmt.Sprintf("%-5s", entry.Level)...)
+	dst = append(dst, " - "...)
+	if columns := processColumns(entry); columns != "" {
+		dst = append(dst, columns...)
+		dst = append(dst, " - "...)
+	}
+	if entry.UserID != "" {
+		dst = append(dst, entry.UserID...)
+		dst = append(dst, " - "...)
+	}
+	if entry.Caller.File != "" {
+		dst = append(dst, entry.Caller.String()...)
+		dst = append(dst, ' ')
+		dst = append(dst, entry.Caller.ShortFunction()...)
+		dst = append(dst, " - "...)
+	}
+	dst = appendIndent(dst, entry, "  ")
+	dst = appendModule(dst, entry)
+	dst = append(dst, entry.Text()...)
+	dst = append(dst, formatTextFields(entry.Fields)...)
+	if entry.TraceID != "" {
+		dst = append(dst, " trace_id="...)
+		dst = append(dst, entry.TraceID...)
+		dst = append(dst, " span_id="...)
+		dst = append(dst, entry.SpanID...)
+	}
+	dst = append(dst, '\n')
+	return dst
+}
+
+// appendIndent appends the indent once for every scope the entry is nested in
+func appendIndent(dst []byte, entry *Entry, indent string) []byte {
+	for i := 0; i < entry.Depth; i++ {
+		dst = append(dst, indent...)
+	}
+	return dst
+}
+
+// appendModule appends "[<module>] " for entries of a module tracer
+func appendModule(dst []byte, entry *Entry) []byte {
+	if entry.Module == "" {
+		return dst
+	}
+	dst = append(dst, '[')
+	dst = append(dst, entry.Module...)
+	return append(dst, "] "...)
+}
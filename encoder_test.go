@@ -0,0 +1,166 @@
+package tracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntry() *Entry {
+	return &Entry{
+		Time:    time.Date(2024, 11, 8, 14, 30, 45, 123000000, time.UTC),
+		Level:   LevelWarn,
+		Color:   "yellow",
+		UserID:  "User123",
+		Message: "Device <2> slow",
+		Fields:  []Field{{Key: "device", Value: 2}, {Key: "err", Value: errors.New("timeout")}},
+	}
+}
+
+// TestJSONEncoder verifies that entries are encoded as one JSON object per line
+func TestJSONEncoder(t *testing.T) {
+	line := JSONEncoder{}.Encode(nil, testEntry())
+	if !strings.HasSuffix(string(line), "}\n") {
+		t.Fatalf("Expected a JSON line, got '%s'", line)
+	}
+
+	var decoded struct {
+		Time    time.Time      `json:"time"`
+		Level   string         `json:"level"`
+		Color   string         `json:"color"`
+		UserID  string         `json:"user_id"`
+		Message string         `json:"message"`
+		Fields  map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(line, &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON line: %v", err)
+	}
+
+	if decoded.Level != "WARN" || decoded.Color != "yellow" || decoded.UserID != "User123" {
+		t.Errorf("Unexpected level, color or user ID: %+v", decoded)
+	}
+	if decoded.Message != "Device <2> slow" {
+		t.Errorf("Expected message to be kept unescaped, got '%s'", decoded.Message)
+	}
+	if decoded.Fields["device"] != float64(2) || decoded.Fields["err"] != "timeout" {
+		t.Errorf("Expected fields to be kept as real values, got %v", decoded.Fields)
+	}
+	if !decoded.Time.Equal(testEntry().Time) {
+		t.Errorf("Expected time %v, got %v", testEntry().Time, decoded.Time)
+	}
+}
+
+// TestTextEncoder verifies the plain text line format
+func TestTextEncoder(t *testing.T) {
+	line := string(TextEncoder{}.Encode(nil, testEntry()))
+	expected := "2024-11-08 14:30:45.123 WARN  - User123 - Device <2> slow device=2 err=timeout\n"
+	if line != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, line)
+	}
+}
+
+// TestEncodeCaller verifies how the caller and module are rendered
+func TestEncodeCaller(t *testing.T) {
+	entry := testEntry()
+	entry.Module = "poller"
+	entry.Caller = Caller{File: "/src/app/poller/device.go", Line: 42, Function: "example.com/app/poller.(*Device).Poll"}
+
+	line := string(TextEncoder{}.Encode(nil, entry))
+	expected := "2024-11-08 14:30:45.123 WARN  - User123 - poller/device.go:42 poller.(*Device).Poll - [poller] Device <2> slow device=2 err=timeout\n"
+	if line != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, line)
+	}
+
+	var decoded struct {
+		Caller struct {
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+			Function string `json:"function"`
+		} `json:"caller"`
+		Module string `json:"module"`
+	}
+	if err := json.Unmarshal(JSONEncoder{}.Encode(nil, entry), &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON line: %v", err)
+	}
+	if decoded.Caller.File != entry.Caller.File || decoded.Caller.Line != 42 || decoded.Caller.Function != entry.Caller.Function || decoded.Module != "poller" {
+		t.Errorf("Unexpected caller or module: %+v", decoded)
+	}
+}
+
+// TestEncoderFileExtension verifies that each format gets its own file and retention
+func TestEncoderFileExtension(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	folderName := "Trace TestEncoder"
+	defer os.RemoveAll(folderName)
+
+	htmlTracer := New(Config{ExecutableName: "TestEncoder"})
+	defer htmlTracer.Close()
+	htmlTracer.Trace("HTML entry")
+
+	jsonTracer := New(Config{ExecutableName: "TestEncoder", Encoder: JSONEncoder{}, MaxSize: 500, MaxFiles: 3})
+	for i := 0; i < 50; i++ {
+		jsonTracer.Tracef("JSON entry %d", i)
+	}
+	// Wait for the background retention
+	jsonTracer.Close()
+
+	content, err := os.ReadFile(filepath.Join(folderName, "trace.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read JSON log file: %v", err)
+	}
+	if !contains(string(content), `"message":"JSON entry 49"`) {
+		t.Error("Expected JSON log file to contain the last entry")
+	}
+
+	jsonFiles, _ := filepath.Glob(filepath.Join(folderName, "*.jsonl"))
+	if len(jsonFiles) > 3 {
+		t.Errorf("Expected at most 3 JSON log files, got %d", len(jsonFiles))
+	}
+	if _, err := os.Stat(filepath.Join(folderName, "trace.html")); err != nil {
+		t.Error("Expected JSON retention to keep the HTML log file")
+	}
+}
+
+// TestEncodePlainText verifies that JSON and text output carry no HTML markup or entities
+func TestEncodePlainText(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{
+		{Writer: &jsonBuf, Encoder: JSONEncoder{}},
+		{Writer: &textBuf, Encoder: TextEncoder{}},
+	}})
+
+	tr.Trace(`a<b & "q"`, RawHTML("<b>x</b>"))
+	tr.ReportException("boom <nil>")
+
+	var decoded struct {
+		Message string `json:"message"`
+	}
+	lines := strings.Split(strings.TrimSpace(jsonBuf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 JSON lines, got:\n%s", jsonBuf.String())
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil || decoded.Message != `a<b & "q" x` {
+		t.Errorf("Expected the plain message, got '%s' (%v)", decoded.Message, err)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &decoded); err != nil || !strings.HasPrefix(decoded.Message, "**** Exception: goroutine ") {
+		t.Errorf("Expected the plain stack trace, got '%s' (%v)", decoded.Message, err)
+	}
+
+	text := textBuf.String()
+	if !contains(text, `INFO  - a<b & "q" x`+"\n") || !contains(text, "Bypassing exception (boom <nil>)") {
+		t.Errorf("Expected plain text lines, got:\n%s", text)
+	}
+	if contains(text, "<code>") || contains(text, "&lt;") {
+		t.Errorf("Expected no markup or entities, got:\n%s", text)
+	}
+}
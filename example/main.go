@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/rphpires/tracer"
@@ -13,6 +14,8 @@ func main() {
 		UserID:         "User123",
 		MaxSize:        5_000_000, // 5MB
 		MaxFiles:       15,
+		// Write the worker names set with SetGoroutineName
+		IncludeGoroutineName: true,
 	})
 
 	// Or just set the user ID
@@ -62,18 +65,19 @@ func riskyOperation() {
 	// Use defer to catch panics
 	defer tracer.RecoverPanic()
 
+	// Log the start and end of the operation with its duration
+	defer tracer.Scope("Risky operation")()
+
 	// Simulate some work
-	tracer.Trace("Starting risky operation...")
 	time.Sleep(100 * time.Millisecond)
 
 	// Uncomment to test panic recovery
 	// panic("Something went wrong!")
-
-	tracer.Trace("Risky operation completed successfully")
 }
 
 // Example of a goroutine with panic recovery
 func workerWithRecovery(id int) {
+	defer tracer.SetGoroutineName(fmt.Sprintf("worker-%d", id))()
 	defer tracer.RecoverPanic()
 
 	tracer.TraceWithColorf("lightgreen", "Worker %d started", id)
@@ -0,0 +1,98 @@
+package tracer
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// badKey is the key used for a trailing value that has no key
+const badKey = "!BADKEY"
+
+// Field is a key/value pair attached to a trace entry
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns the field formatted as key=value.
+// Values containing spaces, quotes or '=' are quoted so they stay greppable.
+func (f Field) String() string {
+	value := fmt.Sprint(f.Value)
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = strconv.Quote(value)
+	}
+	return f.Key + "=" + value
+}
+
+// fieldsFromArgs converts alternating keys and values into fields.
+// Field values are taken as they are, and a value without a key gets the key "!BADKEY".
+func fieldsFromArgs(args []any) []Field {
+	fields := make([]Field, 0, len(args)/2)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case Field:
+			fields = append(fields, arg)
+		case []Field:
+			fields = append(fields, arg...)
+		case string:
+			if i+1 < len(args) {
+				fields = append(fields, Field{Key: arg, Value: args[i+1]})
+				i++
+			} else {
+				fields = append(fields, Field{Key: badKey, Value: arg})
+			}
+		default:
+			fields = append(fields, Field{Key: badKey, Value: arg})
+		}
+	}
+	return fields
+}
+
+// formatTextFields renders fields as " key=value key=value" for plain text output
+func formatTextFields(fields []Field) string {
+	var sb strings.Builder
+	for _, f := range fields {
+		sb.WriteByte(' ')
+		sb.WriteString(f.String())
+	}
+	return sb.String()
+}
+
+// formatHTMLFields renders fields as escaped key=value spans with the "field" CSS class
+func formatHTMLFields(fields []Field) string {
+	var sb strings.Builder
+	for _, f := range fields {
+		sb.WriteString(` <span class="field">`)
+		sb.WriteString(html.EscapeString(f.String()))
+		sb.WriteString(`</span>`)
+	}
+	return sb.String()
+}
+
+// With returns a child tracer that attaches the given fields to every entry.
+// Arguments are alternating keys and values, or Field values.
+// The child shares the folder, configuration and log file of its parent.
+func (t *Tracer) With(args ...any) *Tracer {
+	fields := fieldsFromArgs(args)
+	child := *t
+	child.fields = append(t.fields[:len(t.fields):len(t.fields)], fields...)
+	return &child
+}
+
+// TraceFields writes a message with key/value fields to the trace log with white color.
+// Arguments are alternating keys and values, or Field values.
+func (t *Tracer) TraceFields(msg string, args ...any) {
+	t.traceWithColorInternal(LevelInfo, message{text: msg}, "", fieldsFromArgs(args)...)
+}
+
+// With returns a child of the default tracer that attaches the given fields to every entry
+func With(args ...any) *Tracer {
+	return std.With(args...)
+}
+
+// TraceFields writes a message with key/value fields to the default trace log with white color
+func TraceFields(msg string, args ...any) {
+	stdCaller.TraceFields(msg, args...)
+}
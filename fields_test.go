@@ -0,0 +1,59 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFieldsFromArgs verifies the conversion of key/value arguments into fields
+func TestFieldsFromArgs(t *testing.T) {
+	fields := fieldsFromArgs([]any{"device", 2, Field{Key: "port", Value: "COM3"}, "name", "front door", "dangling"})
+
+	expected := []string{"device=2", "port=COM3", `name="front door"`, `!BADKEY=dangling`}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %d", len(expected), len(fields))
+	}
+	for i, f := range fields {
+		if f.String() != expected[i] {
+			t.Errorf("Expected field '%s', got '%s'", expected[i], f.String())
+		}
+	}
+
+	if _, ok := fields[0].Value.(int); !ok {
+		t.Error("Expected field value to keep its type")
+	}
+}
+
+// TestTraceFields verifies that fields are rendered as spans in the HTML log
+func TestTraceFields(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestFields"})
+	folderName := "Trace TestFields"
+	defer os.RemoveAll(folderName)
+
+	tr.TraceFields("Got message", "ID", 2, "port", 4370)
+
+	poller := tr.With("device", "<door>")
+	poller.Tracef("Polling %s", "now")
+
+	content, err := os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	if !contains(string(content), `Got message <span class="field">ID=2</span> <span class="field">port=4370</span>`) {
+		t.Error("Expected fields to be rendered as key=value spans")
+	}
+	if !contains(string(content), `Polling now <span class="field">device=&lt;door&gt;</span>`) {
+		t.Error("Expected With fields to be attached and escaped")
+	}
+	if len(tr.fields) != 0 {
+		t.Error("Expected With not to change the parent tracer")
+	}
+}
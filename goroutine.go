@@ -0,0 +1,106 @@
+package tracer
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// pid is the process ID written with IncludePID
+var pid = os.Getpid()
+
+// goroutineNames maps goroutine IDs to the names set with SetGoroutineName,
+// and namedGoroutines counts them, so unnamed programs skip the lookup
+var (
+	goroutineNames  sync.Map
+	namedGoroutines atomic.Int64
+)
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine 17 [running]:" header of its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+// SetGoroutineName sets a logical name, such as "worker-3", written with every
+// entry traced from the calling goroutine when IncludeGoroutineName or
+// IncludeGoroutineID is set. It returns a function that removes
+// the name, to be deferred before the goroutine exits:
+//
+//	defer tracer.SetGoroutineName(fmt.Sprintf("worker-%d", id))()
+func SetGoroutineName(name string) func() {
+	id := goroutineID()
+	if _, loaded := goroutineNames.Swap(id, name); !loaded {
+		namedGoroutines.Add(1)
+	}
+	return func() {
+		if _, loaded := goroutineNames.LoadAndDelete(id); loaded {
+			namedGoroutines.Add(-1)
+		}
+	}
+}
+
+// GoroutineName returns the name set for the calling goroutine, if any
+func GoroutineName() string {
+	if namedGoroutines.Load() == 0 {
+		return ""
+	}
+	name, _ := goroutineNames.Load(goroutineID())
+	s, _ := name.(string)
+	return s
+}
+
+// setProcessColumns fills the PID, goroutine ID and goroutine name of the entry,
+// as selected by the config. It runs on the goroutine that traced the entry.
+// The goroutine ID, which takes a stack trace, is only looked up for the columns
+// the config includes.
+func setProcessColumns(entry *Entry, cfg *Config) {
+	if cfg.IncludePID {
+		entry.PID = pid
+	}
+	names := (cfg.IncludeGoroutineID || cfg.IncludeGoroutineName) && namedGoroutines.Load() != 0
+	if !cfg.IncludeGoroutineID && !names {
+		return
+	}
+
+	id := goroutineID()
+	if cfg.IncludeGoroutineID {
+		entry.GoroutineID = id
+	}
+	if name, ok := goroutineNames.Load(id); ok && names {
+		entry.Goroutine = name.(string)
+	}
+}
+
+// processColumns returns the PID and goroutine columns of the entry as text,
+// e.g. "1234 - g17 worker-3", or an empty string when it has none
+func processColumns(entry *Entry) string {
+	var dst []byte
+	if entry.PID != 0 {
+		dst = strconv.AppendInt(dst, int64(entry.PID), 10)
+	}
+	if entry.GoroutineID != 0 || entry.Goroutine != "" {
+		if len(dst) > 0 {
+			dst = append(dst, " - "...)
+		}
+		if entry.GoroutineID != 0 {
+			dst = append(dst, 'g')
+			dst = strconv.AppendUint(dst, entry.GoroutineID, 10)
+			if entry.Goroutine != "" {
+				dst = append(dst, ' ')
+			}
+		}
+		dst = append(dst, entry.Goroutine...)
+	}
+	return string(dst)
+}
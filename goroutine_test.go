@@ -0,0 +1,87 @@
+package tracer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+// TestGoroutineID verifies that every goroutine gets its own ID
+func TestGoroutineID(t *testing.T) {
+	main := goroutineID()
+	if main == 0 {
+		t.Fatal("Expected a goroutine ID")
+	}
+
+	var other uint64
+	done := make(chan struct{})
+	go func() {
+		other = goroutineID()
+		close(done)
+	}()
+	<-done
+
+	if other == 0 || other == main {
+		t.Errorf("Expected distinct goroutine IDs, got %d and %d", main, other)
+	}
+}
+
+// TestGoroutineNames verifies that names are written with the entries of their goroutine only
+func TestGoroutineNames(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{IncludeGoroutineName: true, Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			defer SetGoroutineName(fmt.Sprintf("worker-%d", id))()
+			tr.Tracef("Worker %d started", id)
+		}(i)
+	}
+	wg.Wait()
+	tr.Trace("Unnamed")
+
+	output := buf.String()
+	for i := 1; i <= 3; i++ {
+		if !contains(output, fmt.Sprintf("- worker-%d - Worker %d started", i, i)) {
+			t.Errorf("Expected worker-%d on its entry, got:\n%s", i, output)
+		}
+	}
+	if !contains(output, "INFO  - Unnamed") {
+		t.Errorf("Expected no name on the unnamed goroutine, got:\n%s", output)
+	}
+	if GoroutineName() != "" || namedGoroutines.Load() != 0 {
+		t.Error("Expected the names to be removed")
+	}
+}
+
+// TestProcessColumns verifies the PID and goroutine ID columns
+func TestProcessColumns(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{IncludePID: true, IncludeGoroutineID: true, Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	defer SetGoroutineName("main")()
+	tr.Trace("Columns")
+
+	expected := fmt.Sprintf("INFO  - %d - g%d main - Columns", os.Getpid(), goroutineID())
+	if !contains(buf.String(), expected) {
+		t.Errorf("Expected '%s', got '%s'", expected, buf.String())
+	}
+}
+
+// TestGoroutineNamesOptIn verifies that names are only written when a goroutine column is included
+func TestGoroutineNamesOptIn(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	defer SetGoroutineName("quiet")()
+	tr.Trace("Plain")
+
+	if !contains(buf.String(), "INFO  - Plain") {
+		t.Errorf("Expected no goroutine column, got '%s'", buf.String())
+	}
+}
@@ -0,0 +1,128 @@
+package tracer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// RawHTML is trusted markup that is written to the HTML log without escaping.
+// Pass it as an argument to any trace function to opt in on purpose:
+//
+//	tracer.Trace("Open", tracer.RawHTML(`<a href="report.html">the report</a>`))
+//
+// The markup is written as it is whatever the formatting verb, so %q or %x do not
+// quote or encode it. Never wrap user-supplied text in RawHTML.
+type RawHTML string
+
+// tagPattern matches the tags of trusted markup, stripped from plain-text output
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// colorPattern accepts HTML color names and #rgb/#rrggbb style hex codes
+var colorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{3,8}|[A-Za-z]+)$`)
+
+// validColor reports whether color can be safely used in the color attribute
+func validColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
+
+// message is the text of an entry, and whether the text is trusted HTML markup
+type message struct {
+	text string
+	html bool
+}
+
+// prefix returns the message with an HTML-safe prefix prepended
+func (m message) prefix(p string) message {
+	m.text = p + m.text
+	return m
+}
+
+// rawPlaceholder marks where a RawHTML argument goes while the rest is formatted
+func rawPlaceholder(i int) string {
+	return fmt.Sprintf("\x00raw%d\x00", i)
+}
+
+// placeholder formats as the placeholder itself for any verb, so that verbs such
+// as %q cannot alter it and it can be found again after formatting
+type placeholder string
+
+// Format writes the placeholder verbatim
+func (p placeholder) Format(f fmt.State, _ rune) {
+	f.Write([]byte(p))
+}
+
+// replaceRaw swaps RawHTML arguments for placeholders.
+// It returns nil when there is no RawHTML argument.
+func replaceRaw(a []any) (args []any, raw map[string]string) {
+	for i, arg := range a {
+		r, ok := arg.(RawHTML)
+		if !ok {
+			continue
+		}
+		if raw == nil {
+			raw = make(map[string]string)
+			args = append([]any(nil), a...)
+		}
+		marker := rawPlaceholder(i)
+		raw[marker] = string(r)
+		args[i] = placeholder(marker)
+	}
+	return args, raw
+}
+
+// restoreRaw escapes the formatted text and puts the RawHTML arguments back in place
+func restoreRaw(text string, raw map[string]string) message {
+	text = html.EscapeString(text)
+	for placeholder, markup := range raw {
+		text = strings.ReplaceAll(text, placeholder, markup)
+	}
+	return message{text: text, html: true}
+}
+
+// sprintMessage formats its arguments like fmt.Println, without the trailing newline
+func sprintMessage(a ...any) message {
+	args, raw := replaceRaw(a)
+	if raw == nil {
+		return message{text: sprintln(a...)}
+	}
+	return restoreRaw(sprintln(args...), raw)
+}
+
+// sprintfMessage formats its arguments like fmt.Printf
+func sprintfMessage(format string, a ...any) message {
+	args, raw := replaceRaw(a)
+	if raw == nil {
+		return message{text: fmt.Sprintf(format, a...)}
+	}
+	return restoreRaw(fmt.Sprintf(format, args...), raw)
+}
+
+// htmlMessage returns the message as markup for the HTML log
+func htmlMessage(entry *Entry) string {
+	if entry.HTML {
+		return entry.Message
+	}
+	return html.EscapeString(entry.Message)
+}
+
+// Text returns the message as plain text. The markup of HTML messages, such as
+// RawHTML arguments, is stripped and their entities are decoded.
+func (e *Entry) Text() string {
+	if !e.HTML {
+		return e.Message
+	}
+	return html.UnescapeString(tagPattern.ReplaceAllString(e.Message, ""))
+}
+
+// TraceHTML writes values to the trace log with white color without escaping them.
+// Use it only for trusted markup such as bold text or links.
+func (t *Tracer) TraceHTML(a ...any) {
+	t.traceWithColorInternal(LevelInfo, message{text: sprintln(a...), html: true}, "")
+}
+
+// TraceHTML writes values to the default trace log with white color without escaping them
+func TraceHTML(a ...any) {
+	stdCaller.TraceHTML(a...)
+}
@@ -0,0 +1,83 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidColor verifies that only color names and hex codes are accepted
+func TestValidColor(t *testing.T) {
+	valid := []string{"white", "LightSalmon", "#fff", "#00ff00"}
+	for _, color := range valid {
+		if !validColor(color) {
+			t.Errorf("Expected '%s' to be a valid color", color)
+		}
+	}
+
+	invalid := []string{"", `red" onmouseover="alert(1)`, "red>", "#12", "rgb(1,2,3)"}
+	for _, color := range invalid {
+		if validColor(color) {
+			t.Errorf("Expected '%s' to be rejected", color)
+		}
+	}
+}
+
+// TestHTMLEscaping verifies that messages are escaped unless wrapped in RawHTML
+func TestHTMLEscaping(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestEscaping"})
+	folderName := "Trace TestEscaping"
+	defer os.RemoveAll(folderName)
+	defer tr.Close()
+
+	tr.Trace("<script>alert(1)</script>")
+	tr.Tracef("Device %s connected", "</font><b>")
+	tr.Error("Failed:", "a & b")
+	tr.Trace("See", RawHTML(`<a href="r.html">report</a>`), "for", "<details>")
+	tr.Tracef("%s has %d <alarms>", RawHTML("<b>Door</b>"), 3)
+	tr.Tracef("q=%q x=%x w=%-8v|", RawHTML("<b>q</b>"), RawHTML("<i>x</i>"), RawHTML("<u>w</u>"))
+	tr.TraceHTML("<i>trusted</i>")
+	tr.TraceWithColor(`red" onclick="x`, "Bad color")
+
+	content, err := os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	expected := []string{
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"Device &lt;/font&gt;&lt;b&gt; connected",
+		"** Failed: a &amp; b",
+		`See <a href="r.html">report</a> for &lt;details&gt;`,
+		"<b>Door</b> has 3 &lt;alarms&gt;",
+		"q=<b>q</b> x=<i>x</i> w=<u>w</u>|",
+		"<i>trusted</i>",
+	}
+	for _, e := range expected {
+		if !contains(string(content), e) {
+			t.Errorf("Expected log file to contain '%s'", e)
+		}
+	}
+
+	if contains(string(content), "\x00") {
+		t.Error("Expected no RawHTML placeholder in the log file")
+	}
+	if contains(string(content), "<script>alert") {
+		t.Error("Expected script tag to be escaped")
+	}
+	if contains(string(content), "onclick") {
+		t.Error("Expected invalid color to be rejected")
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasSuffix(line, "Bad color") && !strings.Contains(line, `<font color="white">`) {
+			t.Errorf("Expected invalid color to fall back to the level color, got '%s'", line)
+		}
+	}
+}
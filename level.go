@@ -0,0 +1,69 @@
+package tracer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a trace entry
+type Level int
+
+// Severity levels, from the most verbose to the most severe
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// String returns the upper-case name of the level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "FATAL"
+	}
+	return "UNKNOWN"
+}
+
+// Color returns the HTML color used for entries of this level
+// when no explicit color is given
+func (l Level) Color() string {
+	switch l {
+	case LevelDebug:
+		return "gray"
+	case LevelWarn:
+		return "yellow"
+	case LevelError:
+		return "red"
+	case LevelFatal:
+		return "magenta"
+	}
+	return "white"
+}
+
+// ParseLevel returns the level named by s, such as "debug" or "WARN".
+// "warning" is accepted for LevelWarn.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown level %q", s)
+}
@@ -0,0 +1,96 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLevelColors verifies that each level keeps the colors used before levels existed
+func TestLevelColors(t *testing.T) {
+	tests := []struct {
+		level Level
+		name  string
+		color string
+	}{
+		{LevelDebug, "DEBUG", "gray"},
+		{LevelInfo, "INFO", "white"},
+		{LevelWarn, "WARN", "yellow"},
+		{LevelError, "ERROR", "red"},
+		{LevelFatal, "FATAL", "magenta"},
+	}
+
+	for _, tt := range tests {
+		if tt.level.String() != tt.name {
+			t.Errorf("Expected level name '%s', got '%s'", tt.name, tt.level.String())
+		}
+		if tt.level.Color() != tt.color {
+			t.Errorf("Expected %s color '%s', got '%s'", tt.name, tt.color, tt.level.Color())
+		}
+	}
+}
+
+// TestMinLevel verifies that entries below the threshold are dropped
+func TestMinLevel(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestMinLevel", MinLevel: LevelWarn})
+	folderName := "Trace TestMinLevel"
+	defer os.RemoveAll(folderName)
+
+	tr.Debugf("debug %d", 1)
+	tr.Trace("info message")
+	tr.Warnf("disk at %d%%", 91)
+	tr.Error("failed")
+
+	content, err := os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+
+	if contains(string(content), "debug 1") || contains(string(content), "info message") {
+		t.Error("Expected entries below LevelWarn to be dropped")
+	}
+	if !contains(string(content), `<font color="yellow">`) || !contains(string(content), "disk at 91%") {
+		t.Error("Expected warning entry in yellow")
+	}
+	if !contains(string(content), "** failed") {
+		t.Error("Expected error entry to be written")
+	}
+
+	tr.SetMinLevel(LevelDebug)
+	tr.Debug("now visible")
+
+	content, err = os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !contains(string(content), `<font color="gray">`) || !contains(string(content), "now visible") {
+		t.Error("Expected debug entry in gray after lowering the level")
+	}
+}
+
+// TestParseLevel verifies level names
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"warning": LevelWarn,
+		" error ": LevelError,
+		"Fatal":   LevelFatal,
+	}
+	for name, expected := range tests {
+		level, err := ParseLevel(name)
+		if err != nil || level != expected {
+			t.Errorf("ParseLevel(%q) = %v, %v, expected %v", name, level, err, expected)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package tracer
+
+import "os"
+
+// lockFile is a no-op on platforms without advisory file locking
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without advisory file locking
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package tracer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+const (
+	helperEnv        = "TRACER_TEST_HELPER"
+	helperProcesses  = 4
+	helperEntries    = 300
+	helperFolderName = "Trace TestMultiProcess"
+)
+
+// multiProcessConfig is shared by the parent test and its child processes
+func multiProcessConfig() Config {
+	return Config{
+		ExecutableName: "TestMultiProcess",
+		Encoder:        TextEncoder{},
+		MaxSize:        8_000,
+		MaxFiles:       100,
+		MultiProcess:   true,
+		DisableStdout:  true,
+	}
+}
+
+// TestMultiProcessHelper is the child process of TestMultiProcessWrites
+func TestMultiProcessHelper(t *testing.T) {
+	id := os.Getenv(helperEnv)
+	if id == "" {
+		return
+	}
+
+	tr := New(multiProcessConfig())
+	defer tr.Close()
+	for i := 0; i < helperEntries; i++ {
+		tr.TraceFields("Hammering", "proc", id, "seq", i)
+	}
+}
+
+// TestMultiProcessWrites verifies that processes sharing a folder never lose or corrupt entries
+func TestMultiProcessWrites(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+	os.RemoveAll(helperFolderName)
+	defer os.RemoveAll(helperFolderName)
+
+	children := make([]*exec.Cmd, helperProcesses)
+	for i := range children {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMultiProcessHelper$")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", helperEnv, i))
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start child process: %v", err)
+		}
+		children[i] = cmd
+	}
+	for _, cmd := range children {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("Child process failed: %v", err)
+		}
+	}
+
+	files, err := getLogFiles(helperFolderName, "log")
+	if err != nil {
+		t.Fatalf("Failed to list log files: %v", err)
+	}
+	if len(files) < 2 {
+		t.Errorf("Expected the processes to rotate the file, got %v", files)
+	}
+
+	linePattern := regexp.MustCompile(`^\d{4}-\d\d-\d\d \d\d:\d\d:\d\d\.\d{3} INFO  - Hammering proc=(\d+) seq=(\d+)$`)
+	seen := make(map[string]bool)
+	for _, name := range files {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", name, err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			match := linePattern.FindStringSubmatch(scanner.Text())
+			if match == nil {
+				t.Errorf("Corrupted line in %s: '%s'", filepath.Base(name), scanner.Text())
+				continue
+			}
+			key := match[1] + "/" + match[2]
+			if seen[key] {
+				t.Errorf("Duplicated entry %s", key)
+			}
+			seen[key] = true
+		}
+		file.Close()
+	}
+
+	for i := 0; i < helperProcesses; i++ {
+		for j := 0; j < helperEntries; j++ {
+			if !seen[strconv.Itoa(i)+"/"+strconv.Itoa(j)] {
+				t.Errorf("Missing entry proc=%d seq=%d", i, j)
+			}
+		}
+	}
+}
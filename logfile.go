@@ -0,0 +1,402 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockExtension is appended to the log file name to get the lock file
+// shared by the processes writing to the same folder
+const lockExtension = ".lock"
+
+// logFileCheckInterval is how often a LogFile checks that its handle still
+// refers to the file on disk, which may have been removed or replaced
+const logFileCheckInterval = time.Second
+
+// LogFile represents a log file with rotation capabilities.
+// It keeps one handle open across writes and tracks the file size itself.
+type LogFile struct {
+	filename    string
+	header      []byte
+	footer      []byte
+	maxSize     int64
+	rotation    RotationPolicy
+	rotatedName string
+	shared      bool
+	lock        *os.File
+	periodStart time.Time
+	currentSize int64
+	file        *os.File
+	checkedAt   time.Time
+	mutex       sync.Mutex
+	// rotated is called after the file is opened, with an empty name, and after
+	// every rotation, with the new name of the retired file
+	rotated func(retired string)
+}
+
+// newLogFile creates a new LogFile instance. The file is opened on the first write.
+// The header is written at the start of every new file, and the footer at the end
+// of every retired file.
+func newLogFile(filename string, header, footer []byte, maxSize int64, rotation RotationPolicy) *LogFile {
+	return &LogFile{
+		filename:    filename,
+		header:      header,
+		footer:      footer,
+		maxSize:     maxSize,
+		rotation:    rotation,
+		rotatedName: defaultRotatedName,
+	}
+}
+
+// configure updates the rotation limits, the rotated name template, and whether
+// the file is shared with other processes. When the policy of an open file
+// changes, its period is taken from its last write, so that the file is only
+// retired if it belongs to an earlier period under the new policy.
+func (lf *LogFile) configure(maxSize int64, rotation RotationPolicy, shared bool, rotatedName string) {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	if rotation != lf.rotation && lf.file != nil {
+		lf.periodStart = rotation.periodStart(time.Now())
+		if info, err := lf.file.Stat(); err == nil {
+			lf.periodStart = rotation.periodStart(info.ModTime())
+		}
+	}
+	lf.maxSize = maxSize
+	lf.rotation = rotation
+	lf.shared = shared
+	lf.rotatedName = rotatedName
+}
+
+// lockShared takes the lock shared with the other processes writing to the file.
+// It returns a function releasing the lock.
+func (lf *LogFile) lockShared() (func(), error) {
+	if lf.lock == nil {
+		if err := os.MkdirAll(filepath.Dir(lf.filename), 0755); err != nil {
+			return nil, err
+		}
+		lock, err := os.OpenFile(lf.filename+lockExtension, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, err
+		}
+		lf.lock = lock
+	}
+
+	if err := lockFile(lf.lock); err != nil {
+		return nil, err
+	}
+	return func() {
+		unlockFile(lf.lock)
+	}, nil
+}
+
+// syncShared picks up the changes made by other processes: a rotation replaces
+// the file, so the handle is reopened, and appends grow the file
+func (lf *LogFile) syncShared() error {
+	if lf.file != nil && !lf.isCurrent() {
+		lf.closeFile()
+	}
+	if err := lf.ensureOpen(); err != nil {
+		return err
+	}
+
+	info, err := lf.file.Stat()
+	if err != nil {
+		return err
+	}
+	lf.currentSize = info.Size()
+	return nil
+}
+
+// ensureOpen opens the log file if it is not open yet, or reopens it when the
+// file on disk was removed or replaced since it was opened
+func (lf *LogFile) ensureOpen() error {
+	if lf.file != nil {
+		if time.Since(lf.checkedAt) < logFileCheckInterval {
+			return nil
+		}
+		lf.checkedAt = time.Now()
+		if lf.isCurrent() {
+			return nil
+		}
+		lf.closeFile()
+	}
+
+	dir := filepath.Dir(lf.filename)
+
+	// Create folder if it doesn't exist
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lf.periodStart = lf.rotation.periodStart(now)
+
+	// Rotate a file left over from an earlier period before appending to it
+	if info, err := os.Stat(lf.filename); err == nil && lf.rotation.byInterval() {
+		if filePeriod := lf.rotation.periodStart(info.ModTime()); filePeriod.Before(lf.periodStart) {
+			if err := lf.retire(lf.rotation.periodLabel(filePeriod)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Create log file with its header if it doesn't exist
+	if _, err := os.Stat(lf.filename); os.IsNotExist(err) {
+		if err := createLogFile(lf.filename, lf.header); err != nil {
+			return err
+		}
+	}
+
+	if err := lf.openFile(); err != nil {
+		return err
+	}
+
+	info, err := lf.file.Stat()
+	if err != nil {
+		lf.closeFile()
+		return err
+	}
+	lf.currentSize = info.Size()
+	lf.checkedAt = now
+
+	if lf.rotated != nil {
+		lf.rotated("")
+	}
+	return nil
+}
+
+// isCurrent reports whether the open handle still refers to the file at lf.filename
+func (lf *LogFile) isCurrent() bool {
+	onDisk, err := os.Stat(lf.filename)
+	if err != nil {
+		return false
+	}
+	opened, err := lf.file.Stat()
+	if err != nil {
+		return false
+	}
+	return os.SameFile(onDisk, opened)
+}
+
+func (lf *LogFile) openFile() error {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	if lf.file != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(lf.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	lf.file = file
+	return nil
+}
+
+func (lf *LogFile) closeFile() {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	if lf.file != nil {
+		lf.file.Close()
+		lf.file = nil
+	}
+}
+
+// rotatedStampFormat names files rotated on size, with millisecond precision
+const rotatedStampFormat = "2006-01-02_15_04_05.000"
+
+// retiredName returns the first free name for a retired file, expanding {date}
+// to the stamp, {file} to the current name and {seq} to the lowest free sequence
+// number. The sequence number keeps rotations within the same millisecond or
+// period apart, and names taken by compressed files count as used.
+func (lf *LogFile) retiredName(stamp string) string {
+	dir := filepath.Dir(lf.filename)
+	extension := lf.extension()
+	hasSeq := strings.Contains(lf.rotatedName, "{seq}")
+
+	for seq := 1; ; seq++ {
+		name := strings.NewReplacer(
+			"{date}", stamp,
+			"{file}", filepath.Base(lf.filename),
+			"{seq}", strconv.Itoa(seq),
+		).Replace(lf.rotatedName)
+		name = withExtension(name, extension)
+		if !hasSeq && seq > 1 {
+			// Without the placeholder the sequence number goes before the extension
+			name = fmt.Sprintf("%s_%d.%s", strings.TrimSuffix(name, "."+extension), seq, extension)
+		}
+
+		name = filepath.Join(dir, name)
+		if !fileExists(name) && !fileExists(name+compressedExtension) {
+			return name
+		}
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// retire closes the markup of the current file with the footer and renames it
+// to a free rotated name. The file must not be open.
+func (lf *LogFile) retire(stamp string) error {
+	if len(lf.footer) > 0 {
+		file, err := os.OpenFile(lf.filename, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(lf.footer)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	newFilename := lf.retiredName(stamp)
+	if err := os.Rename(lf.filename, newFilename); err != nil {
+		return err
+	}
+
+	if lf.rotated != nil {
+		lf.rotated(newFilename)
+	}
+	return nil
+}
+
+// rotateFile retires the current file under the given stamp and starts a new one
+// with the header. It is the only rotation routine of the log file.
+func (lf *LogFile) rotateFile(stamp string) error {
+	lf.closeFile()
+
+	if err := lf.retire(stamp); err != nil {
+		return err
+	}
+	return lf.ensureOpen()
+}
+
+// forceRotate rotates the file regardless of its size and period,
+// e.g. to start over after a failed write
+func (lf *LogFile) forceRotate() error {
+	if lf.shared {
+		unlock, err := lf.lockShared()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	lf.closeFile()
+	if _, err := os.Stat(lf.filename); err == nil {
+		if err := lf.retire(time.Now().Format(rotatedStampFormat)); err != nil {
+			return err
+		}
+	}
+	return lf.ensureOpen()
+}
+
+// extension returns the file extension of the log file without the dot
+func (lf *LogFile) extension() string {
+	return strings.TrimPrefix(filepath.Ext(lf.filename), ".")
+}
+
+func (lf *LogFile) write(data []byte) error {
+	if lf.shared {
+		// Serialize writes and rotation with the other processes
+		unlock, err := lf.lockShared()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := lf.syncShared(); err != nil {
+			return err
+		}
+	} else if err := lf.ensureOpen(); err != nil {
+		return err
+	}
+
+	dataLen := int64(len(data))
+	now := time.Now()
+
+	if lf.rotation.byInterval() && !lf.rotation.periodStart(now).Equal(lf.periodStart) {
+		// Name the retired file after the period it covers
+		if err := lf.rotateFile(lf.rotation.periodLabel(lf.periodStart)); err != nil {
+			return err
+		}
+	} else if lf.rotation.bySize() && lf.currentSize+dataLen > lf.maxSize {
+		if err := lf.rotateFile(now.Format(rotatedStampFormat)); err != nil {
+			return err
+		}
+	}
+
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	n, err := lf.file.Write(data)
+	if err != nil {
+		return err
+	}
+
+	lf.currentSize += int64(n)
+	return nil
+}
+
+func (lf *LogFile) close() {
+	lf.closeFile()
+	lf.currentSize = 0
+
+	if lf.lock != nil {
+		lf.lock.Close()
+		lf.lock = nil
+	}
+}
+
+func createLogFile(logFilename string, header []byte) error {
+	file, err := os.Create(logFilename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(header)
+	return err
+}
+
+// getLogFiles returns the log files with the extension, including compressed ones
+func getLogFiles(folderName, extension string) ([]string, error) {
+	pattern := filepath.Join(folderName, "*."+extension)
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := filepath.Glob(pattern + compressedExtension)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, compressed...)
+
+	// Sort by creation time
+	sort.Slice(files, func(i, j int) bool {
+		info1, err1 := os.Stat(files[i])
+		info2, err2 := os.Stat(files[j])
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		return info1.ModTime().Before(info2.ModTime())
+	})
+
+	return files, nil
+}
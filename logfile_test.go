@@ -0,0 +1,161 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countOpenFiles returns the number of open file descriptors of the process
+func countOpenFiles(t *testing.T) int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("Open descriptor count is not available on this platform")
+	}
+	return len(entries)
+}
+
+// TestLogFileDescriptorsStayFlat verifies that tracing does not leak file descriptors
+func TestLogFileDescriptorsStayFlat(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	// Keep the 100k entries off the test output
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}()
+
+	tr := New(Config{ExecutableName: "TestDescriptors", MaxSize: 1_000_000, MaxFiles: 5})
+	folderName := "Trace TestDescriptors"
+	defer os.RemoveAll(folderName)
+	defer tr.Close()
+
+	tr.Trace("Warm up")
+	before := countOpenFiles(t)
+
+	for i := 0; i < 100_000; i++ {
+		tr.Tracef("Entry %d", i)
+	}
+
+	after := countOpenFiles(t)
+	if after > before {
+		t.Errorf("Expected open descriptors to stay at %d, got %d", before, after)
+	}
+}
+
+// TestLogFileReopensAfterRemoval verifies that a removed log file is recreated
+func TestLogFileReopensAfterRemoval(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestReopen"})
+	folderName := "Trace TestReopen"
+	defer os.RemoveAll(folderName)
+	defer tr.Close()
+
+	tr.Trace("Before removal")
+
+	logFilename := filepath.Join(folderName, "trace.html")
+	if err := os.Remove(logFilename); err != nil {
+		t.Fatalf("Failed to remove log file: %v", err)
+	}
+
+	// Skip the wait for the next periodic check
+	tr.logFiles[logFilename].checkedAt = time.Time{}
+	tr.Trace("After removal")
+
+	content, err := os.ReadFile(logFilename)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !contains(string(content), "<!DOCTYPE html>") {
+		t.Error("Expected recreated log file to contain HTML header")
+	}
+	if !contains(string(content), "After removal") {
+		t.Error("Expected entry to be written to the recreated log file")
+	}
+}
+
+// TestRotationKeepsHTMLMarkup verifies that every rotated file starts with the
+// header and ends with the closing tags
+func TestRotationKeepsHTMLMarkup(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	tr := New(Config{ExecutableName: "TestRotationMarkup", MaxSize: 4_000, MaxFiles: 100, DisableStdout: true})
+	folderName := "Trace TestRotationMarkup"
+	defer os.RemoveAll(folderName)
+
+	for i := 0; i < 200; i++ {
+		tr.Tracef("Entry %d", i)
+	}
+	tr.Close()
+
+	files, err := getLogFiles(folderName, "html")
+	if err != nil {
+		t.Fatalf("Failed to list log files: %v", err)
+	}
+	if len(files) < 3 {
+		t.Fatalf("Expected several rotations, got %d files", len(files))
+	}
+
+	current := filepath.Join(folderName, "trace.html")
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if !strings.HasPrefix(string(content), "<!DOCTYPE html>") {
+			t.Errorf("Expected %s to start with the HTML header", file)
+		}
+		if file != current && !strings.HasSuffix(string(content), htmlPageFooter) {
+			t.Errorf("Expected %s to end with the closing tags", file)
+		}
+	}
+}
+
+// TestRotationNamesDoNotCollide verifies that rotations within the same
+// millisecond keep every retired file
+func TestRotationNamesDoNotCollide(t *testing.T) {
+	folderName := "Trace TestRotationNames"
+	defer os.RemoveAll(folderName)
+
+	logFile := newLogFile(filepath.Join(folderName, "trace.log"), nil, nil, 1_000, RotateBySize)
+	defer logFile.close()
+
+	stamp := time.Now().Format(rotatedStampFormat)
+	for i := 0; i < 3; i++ {
+		if err := logFile.write([]byte("entry\n")); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		if err := logFile.rotateFile(stamp); err != nil {
+			t.Fatalf("Failed to rotate: %v", err)
+		}
+	}
+
+	for seq := 1; seq <= 3; seq++ {
+		name := filepath.Join(folderName, fmt.Sprintf("%s_%d_trace.log", stamp, seq))
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("Expected rotated file %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package tracer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// moduleRule sets the level of the modules matching a pattern
+type moduleRule struct {
+	pattern string
+	level   Level
+	// off turns the modules off, inherit keeps the level of the tracer
+	off     bool
+	inherit bool
+}
+
+// moduleRules is a parsed module list such as "poller=debug,http=warn,*=info"
+type moduleRules []moduleRule
+
+// parseModules parses a comma-separated list of name=level entries. The level may
+// also be "off" or "on", and a name alone means name=on. Names may contain the
+// wildcards of path.Match, and "*" matches every module. When the list has bare
+// names, the modules it does not match are off.
+func parseModules(spec string) (moduleRules, error) {
+	var rules moduleRules
+	bare := false
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(item, "=")
+		rule := moduleRule{pattern: strings.TrimSpace(name)}
+		if _, err := path.Match(rule.pattern, ""); err != nil || rule.pattern == "" {
+			return nil, fmt.Errorf("invalid module pattern %q", name)
+		}
+
+		switch value = strings.ToLower(strings.TrimSpace(value)); {
+		case !hasValue:
+			rule.inherit, bare = true, true
+		case value == "on":
+			rule.inherit = true
+		case value == "off":
+			rule.off = true
+		default:
+			level, err := ParseLevel(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.level = level
+		}
+		rules = append(rules, rule)
+	}
+
+	if bare {
+		rules = append(rules, moduleRule{pattern: "*", off: true})
+	}
+	return rules, nil
+}
+
+// match returns the rule for the module, or for its closest parent, so that
+// "poller=off" also turns off poller.tcp. At each level an exact name comes first,
+// then the first matching pattern in list order. "*" only applies when no rule
+// matches the module or its parents.
+func (rules moduleRules) match(module string) (moduleRule, bool) {
+	for name := module; name != ""; name = parentModule(name) {
+		for _, rule := range rules {
+			if rule.pattern == name {
+				return rule, true
+			}
+		}
+		for _, rule := range rules {
+			if ok, _ := path.Match(rule.pattern, name); ok && rule.pattern != "*" {
+				return rule, true
+			}
+		}
+	}
+	for _, rule := range rules {
+		if rule.pattern == "*" {
+			return rule, true
+		}
+	}
+	return moduleRule{}, false
+}
+
+// parentModule returns the module a nested module belongs to, e.g. "poller" for
+// "poller.tcp", or an empty string for a top-level module
+func parentModule(module string) string {
+	if i := strings.LastIndexByte(module, '.'); i >= 0 {
+		return module[:i]
+	}
+	return ""
+}
+
+// moduleOverride is a level or enable switch set at runtime for one module
+type moduleOverride struct {
+	level     Level
+	hasLevel  bool
+	disabled  bool
+	hasSwitch bool
+}
+
+// Module returns a child tracer whose entries are tagged with the module name.
+// Its level and enable switch can be set with SetModuleLevel, SetModuleEnabled
+// and Config.Modules. The module of a module tracer is nested, e.g. "poller.tcp".
+func (t *Tracer) Module(name string) *Tracer {
+	child := *t
+	if t.module != "" {
+		name = t.module + "." + name
+	}
+	child.module = name
+	return &child
+}
+
+// SetModuleLevel sets the minimum level of the entries of a module and of its
+// nested modules, taking precedence over Config.Modules and MinLevel
+func (t *Tracer) SetModuleLevel(module string, level Level) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	override := t.moduleOverrides[module]
+	override.level, override.hasLevel = level, true
+	t.setModuleOverride(module, override)
+}
+
+// SetModuleEnabled turns the entries of a module and of its nested modules on or off
+func (t *Tracer) SetModuleEnabled(module string, enabled bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	override := t.moduleOverrides[module]
+	override.disabled, override.hasSwitch = !enabled, true
+	t.setModuleOverride(module, override)
+}
+
+// SetModules replaces Config.Modules with a list such as "poller=debug,http=warn,*=info"
+func (t *Tracer) SetModules(spec string) error {
+	if _, err := parseModules(spec); err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.config.Modules = spec
+	return nil
+}
+
+// setModuleOverride stores the override of a module. The mutex must be held.
+func (c *core) setModuleOverride(module string, override moduleOverride) {
+	if c.moduleOverrides == nil {
+		c.moduleOverrides = make(map[string]moduleOverride)
+	}
+	c.moduleOverrides[module] = override
+}
+
+// minLevel returns the minimum level of the entries of the tracer. Entries of a
+// module that is turned off are dropped, reported by false.
+func (t *Tracer) minLevel(cfg *Config) (Level, bool) {
+	if t.module == "" {
+		return cfg.MinLevel, true
+	}
+
+	t.mutex.Lock()
+	override := t.moduleOverrideLocked(t.module)
+	rules, err := t.moduleRulesLocked(cfg.Modules)
+	t.mutex.Unlock()
+
+	if override.disabled {
+		return 0, false
+	}
+	if override.hasLevel {
+		return override.level, true
+	}
+	if err != nil {
+		return cfg.MinLevel, true
+	}
+	if rule, ok := rules.match(t.module); ok {
+		switch {
+		case rule.off:
+			return 0, false
+		case rule.inherit:
+			return cfg.MinLevel, true
+		}
+		return rule.level, true
+	}
+	return cfg.MinLevel, true
+}
+
+// moduleOverrideLocked returns the switch and the level set at runtime for the
+// module, each taken from the module itself or else from its closest parent that
+// has one. The mutex must be held.
+func (c *core) moduleOverrideLocked(module string) moduleOverride {
+	var resolved moduleOverride
+	for name := module; name != ""; name = parentModule(name) {
+		override, ok := c.moduleOverrides[name]
+		if !ok {
+			continue
+		}
+		if override.hasSwitch && !resolved.hasSwitch {
+			resolved.disabled, resolved.hasSwitch = override.disabled, true
+		}
+		if override.hasLevel && !resolved.hasLevel {
+			resolved.level, resolved.hasLevel = override.level, true
+		}
+	}
+	return resolved
+}
+
+// moduleRulesLocked returns the parsed module list, parsing it again only when it
+// changed. The mutex must be held.
+func (c *core) moduleRulesLocked(spec string) (moduleRules, error) {
+	if spec == c.moduleSpec {
+		return c.moduleRules, nil
+	}
+	rules, err := parseModules(spec)
+	if err != nil {
+		return nil, err
+	}
+	c.moduleSpec, c.moduleRules = spec, rules
+	return rules, nil
+}
+
+// Module returns a child of the default tracer tagged with the module name
+func Module(name string) *Tracer {
+	return std.Module(name)
+}
+
+// SetModuleLevel sets the minimum level of a module of the default tracer
+func SetModuleLevel(module string, level Level) {
+	std.SetModuleLevel(module, level)
+}
+
+// SetModuleEnabled turns a module of the default tracer on or off
+func SetModuleEnabled(module string, enabled bool) {
+	std.SetModuleEnabled(module, enabled)
+}
+
+// SetModules replaces the module list of the default tracer
+func SetModules(spec string) error {
+	return std.SetModules(spec)
+}
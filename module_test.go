@@ -0,0 +1,110 @@
+package tracer
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestParseModules verifies module lists and how they resolve module levels
+func TestParseModules(t *testing.T) {
+	tests := []struct {
+		spec    string
+		module  string
+		level   Level
+		enabled bool
+	}{
+		{"poller=debug,http=warn,*=info", "poller", LevelDebug, true},
+		{"poller=debug,http=warn,*=info", "http", LevelWarn, true},
+		{"poller=debug,http=warn,*=info", "db", LevelInfo, true},
+		{"poller*=error,poller.tcp=debug", "poller.tcp", LevelDebug, true},
+		{"poller*=error,poller.tcp=debug", "poller.udp", LevelError, true},
+		{"poller,http", "http", LevelWarn, true},
+		{"poller,http", "db", 0, false},
+		{"poller=off", "poller", 0, false},
+		{"poller=off,*=info", "poller.tcp", 0, false},
+		{"poller=debug,*=error", "poller.tcp.rx", LevelDebug, true},
+		{"poller=off,poller.tcp=info", "poller.tcp", LevelInfo, true},
+		{"poller", "poller.tcp", LevelWarn, true},
+		{"", "poller", LevelWarn, true},
+	}
+
+	for _, tt := range tests {
+		tr := New(Config{Modules: tt.spec, MinLevel: LevelWarn})
+		cfg := tr.config
+		level, enabled := tr.Module(tt.module).minLevel(&cfg)
+		if enabled != tt.enabled || enabled && level != tt.level {
+			t.Errorf("%q for %s: expected %v/%v, got %v/%v", tt.spec, tt.module, tt.level, tt.enabled, level, enabled)
+		}
+	}
+
+	for _, spec := range []string{"poller=loud", "=debug", "[=info"} {
+		if _, err := parseModules(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+// TestModuleTracer verifies module tags and the runtime level and enable switch
+func TestModuleTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{
+		MinLevel: LevelInfo,
+		Sinks:    []Sink{{Writer: &buf, Encoder: TextEncoder{}}},
+	})
+	poller := tr.Module("poller")
+
+	poller.Debug("Hidden")
+	tr.SetModuleLevel("poller", LevelDebug)
+	poller.Debug("Raw frame")
+	tr.Debug("Still hidden")
+	poller.Module("tcp").Info("Connected")
+
+	tr.SetModuleEnabled("poller", false)
+	poller.Error("Muted")
+	tr.SetModuleEnabled("poller", true)
+	poller.Info("Back")
+
+	output := buf.String()
+	for _, expected := range []string{"[poller] Raw frame", "[poller.tcp] Connected", "[poller] Back"} {
+		if !contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	for _, hidden := range []string{"Hidden", "Still hidden", "Muted"} {
+		if contains(output, hidden) {
+			t.Errorf("Expected %q to be dropped", hidden)
+		}
+	}
+}
+
+// TestNestedModuleSwitches verifies that the switch and level of a module apply to its nested modules
+func TestNestedModuleSwitches(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{
+		MinLevel: LevelInfo,
+		Sinks:    []Sink{{Writer: &buf, Encoder: TextEncoder{}}},
+	})
+	tcp := tr.Module("poller").Module("tcp")
+
+	tr.SetModuleEnabled("poller", false)
+	tcp.Error("Muted with its parent")
+	tr.SetModuleEnabled("poller.tcp", true)
+	tcp.Info("Turned back on")
+
+	tr.SetModuleLevel("poller", LevelDebug)
+	tcp.Debug("Parent level")
+	tr.SetModuleLevel("poller.tcp", LevelWarn)
+	tcp.Info("Hidden by its own level")
+
+	output := buf.String()
+	for _, expected := range []string{"[poller.tcp] Turned back on", "[poller.tcp] Parent level"} {
+		if !contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	for _, hidden := range []string{"Muted with its parent", "Hidden by its own level"} {
+		if contains(output, hidden) {
+			t.Errorf("Expected %q to be dropped", hidden)
+		}
+	}
+}
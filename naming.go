@@ -0,0 +1,104 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultFileName is the name of the current log file
+	defaultFileName = "trace.{ext}"
+	// defaultRotatedName is the name of a retired log file
+	defaultRotatedName = "{date}_{seq}_{file}"
+)
+
+// logDir returns the folder of the log files. In order of precedence it is the
+// directory named by the DirEnv variable, Dir, the XDG state directory when
+// UseStateDir is set, and "Trace <ExecutableName>" in the working directory.
+// Relative directories are resolved against the directory of the executable.
+func (cfg *Config) logDir() string {
+	dir := cfg.Dir
+	if cfg.DirEnv != "" {
+		if value := os.Getenv(cfg.DirEnv); value != "" {
+			dir = value
+		}
+	}
+
+	if dir == "" {
+		if cfg.UseStateDir {
+			if stateDir := stateHome(); stateDir != "" {
+				return filepath.Join(stateDir, cfg.ExecutableName)
+			}
+		}
+		return "Trace " + cfg.ExecutableName
+	}
+
+	if !filepath.IsAbs(dir) {
+		if executable, err := os.Executable(); err == nil {
+			dir = filepath.Join(filepath.Dir(executable), dir)
+		}
+	}
+	return dir
+}
+
+// stateHome returns $XDG_STATE_HOME, or ~/.local/state when it is not set
+func stateHome() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state")
+}
+
+// nameTemplates returns the current and rotated file name templates for the
+// extension, with the placeholders known up front already expanded
+func (cfg *Config) nameTemplates(extension string) (fileName, rotatedName string) {
+	fileName, rotatedName = cfg.FileName, cfg.RotatedName
+	if fileName == "" {
+		fileName = defaultFileName
+	}
+	if rotatedName == "" {
+		rotatedName = defaultRotatedName
+	}
+
+	replacer := strings.NewReplacer(
+		"{name}", cfg.ExecutableName,
+		"{ext}", extension,
+		"{pid}", strconv.Itoa(os.Getpid()),
+	)
+	return withExtension(replacer.Replace(fileName), extension),
+		replacer.Replace(rotatedName)
+}
+
+// otherCurrentFiles matches the names of the current log files of every process
+// when the file name template has {pid}, so retention leaves the files that other
+// running processes write to. It returns nil when the file name has no {pid}.
+func (cfg *Config) otherCurrentFiles(extension string) *regexp.Regexp {
+	fileName := cfg.FileName
+	if !strings.Contains(fileName, "{pid}") {
+		return nil
+	}
+
+	replacer := strings.NewReplacer(
+		"{name}", cfg.ExecutableName,
+		"{ext}", extension,
+	)
+	pattern := regexp.QuoteMeta(withExtension(replacer.Replace(fileName), extension))
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("{pid}"), `\d+`)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+// withExtension appends the extension to name unless it already ends with it,
+// so retention finds every file of the format
+func withExtension(name, extension string) string {
+	if strings.HasSuffix(name, "."+extension) {
+		return name
+	}
+	return name + "." + extension
+}
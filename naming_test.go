@@ -0,0 +1,75 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLogDir verifies the precedence of the log folder settings
+func TestLogDir(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Failed to get executable: %v", err)
+	}
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+	t.Setenv("TRACER_TEST_DIR", "/var/log/myapp")
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{"default", Config{ExecutableName: "MyApp"}, "Trace MyApp"},
+		{"absolute", Config{ExecutableName: "MyApp", Dir: "/tmp/logs"}, "/tmp/logs"},
+		{"relative", Config{ExecutableName: "MyApp", Dir: "logs"}, filepath.Join(filepath.Dir(executable), "logs")},
+		{"state", Config{ExecutableName: "MyApp", UseStateDir: true}, filepath.Join(stateDir, "MyApp")},
+		{"env", Config{ExecutableName: "MyApp", Dir: "/tmp/logs", DirEnv: "TRACER_TEST_DIR"}, "/var/log/myapp"},
+		{"env unset", Config{ExecutableName: "MyApp", Dir: "/tmp/logs", DirEnv: "TRACER_TEST_UNSET"}, "/tmp/logs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if dir := tt.cfg.logDir(); dir != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, dir)
+			}
+		})
+	}
+}
+
+// TestFileNameTemplates verifies the current and rotated file name templates
+func TestFileNameTemplates(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	dir := t.TempDir()
+	tr := New(Config{
+		ExecutableName: "MyApp",
+		Dir:            dir,
+		FileName:       "{name}.{ext}",
+		RotatedName:    "{name}-{date}-{pid}",
+		Encoder:        TextEncoder{},
+		MaxSize:        200,
+		MaxFiles:       100,
+		DisableStdout:  true,
+	})
+
+	for i := 0; i < 30; i++ {
+		tr.Tracef("Entry %d", i)
+	}
+	tr.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, "MyApp.log")); err != nil {
+		t.Errorf("Expected current file MyApp.log: %v", err)
+	}
+
+	rotated, _ := filepath.Glob(filepath.Join(dir, fmt.Sprintf("MyApp-*-%d*.log", os.Getpid())))
+	if len(rotated) < 2 {
+		t.Fatalf("Expected rotated files named from the template, got %v", rotated)
+	}
+}
@@ -0,0 +1,165 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// retentionInterval is how often MaxAge is enforced between rotations, so that
+// old files are removed from folders whose log file rarely rotates
+const retentionInterval = time.Hour
+
+// retentionSweep is the periodic retention pass of a core
+type retentionSweep struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startSweep starts the periodic retention pass unless it is running.
+// The write mutex must be held.
+func (c *core) startSweep() {
+	if c.sweep != nil {
+		return
+	}
+	interval := c.sweepInterval
+	if interval <= 0 {
+		interval = retentionInterval
+	}
+	sweep := &retentionSweep{stop: make(chan struct{}), done: make(chan struct{})}
+	c.sweep = sweep
+	go c.runSweep(sweep, interval)
+}
+
+// runSweep checks the retention limits of the open log files on every tick
+// while MaxAge is set
+func (c *core) runSweep(sweep *retentionSweep, interval time.Duration) {
+	defer close(sweep.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sweep.stop:
+			return
+		case <-ticker.C:
+			if c.currentConfig().MaxAge <= 0 {
+				continue
+			}
+			c.writeMutex.Lock()
+			names := make([]string, 0, len(c.logFiles))
+			for name := range c.logFiles {
+				names = append(names, name)
+			}
+			c.writeMutex.Unlock()
+
+			for _, name := range names {
+				c.afterRotation(name, "")
+			}
+		}
+	}
+}
+
+// stopSweep stops the periodic retention pass and waits until it returns
+func (c *core) stopSweep() {
+	c.writeMutex.Lock()
+	sweep := c.sweep
+	c.sweep = nil
+	c.writeMutex.Unlock()
+
+	if sweep != nil {
+		close(sweep.stop)
+		<-sweep.done
+	}
+}
+
+// afterRotation compresses the retired file, if any, and removes the log files
+// beyond the retention limits. It runs in the background, never on the trace path.
+func (c *core) afterRotation(logFilename, retired string) {
+	cfg := c.currentConfig()
+
+	c.background.Add(1)
+	go func() {
+		defer c.background.Done()
+
+		c.cleanupMutex.Lock()
+		defer c.cleanupMutex.Unlock()
+
+		if retired != "" && cfg.Compress {
+			if err := compressLogFile(retired); err != nil {
+				fmt.Printf("Error compressing log file: %v\n", err)
+			}
+		}
+
+		if err := removeOldLogFiles(logFilename, cfg); err != nil {
+			fmt.Printf("Error removing old log files: %v\n", err)
+		}
+	}()
+}
+
+// removeOldLogFiles removes, in a single pass, the oldest rotated files of the format
+// of logFilename until the MaxFiles, MaxAge and MaxTotalBytes limits are met.
+// The current file counts towards MaxFiles and MaxTotalBytes but is never removed.
+// With {pid} in the file name, the current files of the other processes are skipped.
+func removeOldLogFiles(logFilename string, cfg Config) error {
+	dir := filepath.Dir(logFilename)
+	extension := strings.TrimPrefix(filepath.Ext(logFilename), ".")
+
+	logFiles, err := getLogFiles(dir, extension)
+	if err != nil {
+		return err
+	}
+
+	type rotatedFile struct {
+		name string
+		info os.FileInfo
+	}
+
+	otherCurrent := cfg.otherCurrentFiles(extension)
+
+	var rotated []rotatedFile
+	var totalBytes int64
+	for _, name := range logFiles {
+		current := filepath.Clean(name) == filepath.Clean(logFilename)
+		if !current && otherCurrent != nil && otherCurrent.MatchString(filepath.Base(name)) {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+		totalBytes += info.Size()
+		if !current {
+			rotated = append(rotated, rotatedFile{name: name, info: info})
+		}
+	}
+
+	var cutoff time.Time
+	if cfg.MaxAge > 0 {
+		cutoff = time.Now().Add(-cfg.MaxAge)
+	}
+
+	// Files are sorted oldest first, and the current file takes one MaxFiles slot
+	for len(rotated) > 0 {
+		oldest := rotated[0]
+		tooMany := cfg.MaxFiles > 0 && len(rotated) > cfg.MaxFiles-1
+		tooOld := !cutoff.IsZero() && oldest.info.ModTime().Before(cutoff)
+		tooBig := cfg.MaxTotalBytes > 0 && totalBytes > cfg.MaxTotalBytes
+		if !tooMany && !tooOld && !tooBig {
+			break
+		}
+
+		if err := os.Remove(oldest.name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if cfg.OnRemove != nil {
+			cfg.OnRemove(oldest.name)
+		}
+		totalBytes -= oldest.info.Size()
+		rotated = rotated[1:]
+	}
+
+	return nil
+}
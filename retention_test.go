@@ -0,0 +1,150 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// writeAgedFiles creates rotated log files, the first one being the oldest
+func writeAgedFiles(t *testing.T, folderName string, ages []time.Duration, size int) []string {
+	if err := os.MkdirAll(folderName, 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	var names []string
+	for i, age := range ages {
+		name := filepath.Join(folderName, fmt.Sprintf("2024-11-%02d_trace.log", i+1))
+		if err := os.WriteFile(name, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to create log file: %v", err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set file time: %v", err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// TestRetentionLimits verifies MaxFiles, MaxAge and MaxTotalBytes in a single pass
+func TestRetentionLimits(t *testing.T) {
+	folderName := "Trace TestRetention"
+	defer os.RemoveAll(folderName)
+
+	day := 24 * time.Hour
+	tests := []struct {
+		name string
+		cfg  Config
+		kept int
+	}{
+		{"MaxFiles", Config{MaxFiles: 3}, 2},
+		{"MaxAge", Config{MaxFiles: 100, MaxAge: 30 * day}, 3},
+		{"MaxTotalBytes", Config{MaxFiles: 100, MaxTotalBytes: 450}, 3},
+		{"Combined", Config{MaxFiles: 5, MaxAge: 45 * day, MaxTotalBytes: 1000}, 4},
+	}
+
+	for _, tt := range tests {
+		os.RemoveAll(folderName)
+		names := writeAgedFiles(t, folderName, []time.Duration{60 * day, 50 * day, 40 * day, 20 * day, 10 * day, day}, 100)
+		current := filepath.Join(folderName, "trace.log")
+		if err := os.WriteFile(current, make([]byte, 100), 0644); err != nil {
+			t.Fatalf("Failed to create current log file: %v", err)
+		}
+
+		var removed []string
+		tt.cfg.OnRemove = func(name string) { removed = append(removed, name) }
+		if err := removeOldLogFiles(current, tt.cfg); err != nil {
+			t.Fatalf("%s: retention failed: %v", tt.name, err)
+		}
+
+		remaining, _ := getLogFiles(folderName, "log")
+		if len(remaining) != tt.kept+1 {
+			t.Errorf("%s: expected %d rotated files and the current one, got %v", tt.name, tt.kept, remaining)
+		}
+		if _, err := os.Stat(current); err != nil {
+			t.Errorf("%s: expected the current file to be kept", tt.name)
+		}
+
+		expectedRemoved := names[:len(names)-tt.kept]
+		sort.Strings(removed)
+		if fmt.Sprint(removed) != fmt.Sprint(expectedRemoved) {
+			t.Errorf("%s: expected OnRemove for %v, got %v", tt.name, expectedRemoved, removed)
+		}
+	}
+}
+
+// TestRetentionSweep verifies that MaxAge is enforced on a log file that does not rotate
+func TestRetentionSweep(t *testing.T) {
+	dir := t.TempDir()
+	removed := make(chan string, 1)
+	tr := New(Config{
+		Dir:           dir,
+		Enabled:       EnableOn,
+		Encoder:       TextEncoder{},
+		MaxAge:        24 * time.Hour,
+		DisableStdout: true,
+		OnRemove:      func(name string) { removed <- name },
+	})
+	// Set before the first write starts the sweep
+	tr.sweepInterval = 10 * time.Millisecond
+	defer tr.Close()
+
+	// A rotated file that turns old while the current file stays open
+	names := writeAgedFiles(t, dir, []time.Duration{time.Hour}, 100)
+	tr.Trace("Open the log file")
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(names[0], old, old); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+
+	select {
+	case name := <-removed:
+		if name != names[0] {
+			t.Errorf("Expected %s to be removed, got %s", names[0], name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the old file to be removed without a rotation")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "trace.log")); err != nil {
+		t.Errorf("Expected the current file to be kept: %v", err)
+	}
+}
+
+// TestRetentionKeepsOtherProcessFiles verifies that with {pid} in the file name,
+// retention never removes the current file of another process
+func TestRetentionKeepsOtherProcessFiles(t *testing.T) {
+	dir := t.TempDir()
+	rotated := writeAgedFiles(t, dir, []time.Duration{30 * time.Hour, 20 * time.Hour, 10 * time.Hour}, 100)
+	other := filepath.Join(dir, "trace_99999.log")
+	if err := os.WriteFile(other, make([]byte, 100), 0644); err != nil {
+		t.Fatalf("Failed to create the other process file: %v", err)
+	}
+	old := time.Now().Add(-40 * time.Hour)
+	if err := os.Chtimes(other, old, old); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+
+	tr := New(Config{
+		Dir:           dir,
+		FileName:      "trace_{pid}",
+		Enabled:       EnableOn,
+		Encoder:       TextEncoder{},
+		MaxFiles:      3,
+		DisableStdout: true,
+	})
+	tr.Trace("Open the log file")
+	tr.Close()
+
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected the other process file to be kept: %v", err)
+	}
+	if _, err := os.Stat(rotated[0]); err == nil {
+		t.Error("Expected the oldest rotated file to be removed")
+	}
+	if _, err := os.Stat(rotated[2]); err != nil {
+		t.Errorf("Expected the newest rotated file to be kept: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package tracer
+
+import "time"
+
+// RotationPolicy selects when the current log file is rotated.
+// Policies can be combined, e.g. RotateBySize|RotateDaily.
+type RotationPolicy uint8
+
+const (
+	// RotateBySize rotates the file when it would grow beyond MaxSize (default)
+	RotateBySize RotationPolicy = 1 << iota
+	// RotateHourly rotates the file at the start of every local hour
+	RotateHourly
+	// RotateDaily rotates the file at local midnight
+	RotateDaily
+)
+
+// bySize reports whether the policy rotates on size. The zero policy rotates on size.
+func (p RotationPolicy) bySize() bool {
+	return p == 0 || p&RotateBySize != 0
+}
+
+// byInterval reports whether the policy rotates at hour or day boundaries
+func (p RotationPolicy) byInterval() bool {
+	return p&(RotateHourly|RotateDaily) != 0
+}
+
+// periodStart returns the start of the local hour or day containing t,
+// or the zero time when the policy has no interval
+func (p RotationPolicy) periodStart(t time.Time) time.Time {
+	t = t.Local()
+	switch {
+	case p&RotateHourly != 0:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+	case p&RotateDaily != 0:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+	return time.Time{}
+}
+
+// periodLabel names the period starting at start, used in rotated file names:
+// "2024-11-08" for daily files and "2024-11-08_14" for hourly files
+func (p RotationPolicy) periodLabel(start time.Time) string {
+	if p&RotateHourly != 0 {
+		return start.Format("2006-01-02_15")
+	}
+	return start.Format("2006-01-02")
+}
@@ -0,0 +1,130 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRotationPeriods verifies period boundaries and labels of the interval policies
+func TestRotationPeriods(t *testing.T) {
+	at := time.Date(2024, 11, 8, 14, 30, 45, 0, time.Local)
+
+	if got := RotateDaily.periodStart(at); !got.Equal(time.Date(2024, 11, 8, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Unexpected daily period start %v", got)
+	}
+	if got := RotateHourly.periodStart(at); !got.Equal(time.Date(2024, 11, 8, 14, 0, 0, 0, time.Local)) {
+		t.Errorf("Unexpected hourly period start %v", got)
+	}
+	if got := RotateDaily.periodLabel(RotateDaily.periodStart(at)); got != "2024-11-08" {
+		t.Errorf("Expected daily label '2024-11-08', got '%s'", got)
+	}
+	if got := (RotateBySize | RotateHourly).periodLabel(at); got != "2024-11-08_14" {
+		t.Errorf("Expected hourly label '2024-11-08_14', got '%s'", got)
+	}
+
+	if !RotationPolicy(0).bySize() || RotationPolicy(0).byInterval() {
+		t.Error("Expected the zero policy to rotate on size only")
+	}
+	if RotateDaily.bySize() || !(RotateBySize | RotateDaily).bySize() {
+		t.Error("Expected size rotation only when RotateBySize is set")
+	}
+}
+
+// TestDailyRotation verifies rotation of a file from an earlier day at start and at the boundary
+func TestDailyRotation(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	folderName := "Trace TestDaily"
+	defer os.RemoveAll(folderName)
+
+	// Leave yesterday's file behind, as a previous run would
+	yesterday := time.Now().AddDate(0, 0, -1)
+	logFilename := filepath.Join(folderName, "trace.html")
+	if err := os.MkdirAll(folderName, 0755); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	if err := os.WriteFile(logFilename, []byte(htmlPageHeader+"Yesterday entry"), 0644); err != nil {
+		t.Fatalf("Failed to create log file: %v", err)
+	}
+	if err := os.Chtimes(logFilename, yesterday, yesterday); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+
+	tr := New(Config{ExecutableName: "TestDaily", Rotation: RotateDaily, DisableStdout: true})
+	defer tr.Close()
+	tr.Trace("Today entry")
+
+	rotated := filepath.Join(folderName, yesterday.Format("2006-01-02")+"_1_trace.html")
+	content, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatalf("Expected yesterday's file to be rotated at start: %v", err)
+	}
+	if !contains(string(content), "Yesterday entry") || contains(string(content), "Today entry") {
+		t.Error("Expected the rotated file to hold only yesterday's entries")
+	}
+
+	// Pretend the current file was opened two days ago to cross the boundary
+	twoDaysAgo := RotateDaily.periodStart(time.Now().AddDate(0, 0, -2))
+	tr.logFiles[logFilename].periodStart = twoDaysAgo
+	tr.Trace("After midnight")
+
+	content, err = os.ReadFile(filepath.Join(folderName, twoDaysAgo.Format("2006-01-02")+"_1_trace.html"))
+	if err != nil {
+		t.Fatalf("Expected the file to be rotated at the day boundary: %v", err)
+	}
+	if !contains(string(content), "Today entry") {
+		t.Error("Expected the rotated file to hold the entries of its period")
+	}
+
+	content, err = os.ReadFile(logFilename)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !contains(string(content), "After midnight") || contains(string(content), "Today entry") {
+		t.Error("Expected the new file to start with the new period")
+	}
+}
+
+// TestRotationPolicyChange verifies that enabling an interval policy on an open file
+// keeps the file of the current period and retires one from an earlier period
+func TestRotationPolicyChange(t *testing.T) {
+	dir := t.TempDir()
+	tr := New(Config{Dir: dir, Enabled: EnableOn, DisableStdout: true})
+	defer tr.Close()
+	tr.Trace("Before the change")
+
+	tr.SetConfig(Config{Rotation: RotateDaily})
+	tr.Trace("After the change")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.html"))
+	if len(files) != 1 {
+		t.Fatalf("Expected the current file to be kept, got %v", files)
+	}
+
+	// A file last written yesterday belongs to the previous day
+	logFilename := filepath.Join(dir, "trace.html")
+	yesterday := time.Now().AddDate(0, 0, -1)
+	if err := os.Chtimes(logFilename, yesterday, yesterday); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+	tr.SetConfig(Config{Rotation: RotateHourly})
+	tr.Trace("Next period")
+
+	rotated := filepath.Join(dir, RotateHourly.periodLabel(RotateHourly.periodStart(yesterday))+"_1_trace.html")
+	content, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatalf("Expected the file to be retired under its own period: %v", err)
+	}
+	if !contains(string(content), "After the change") || contains(string(content), "Next period") {
+		t.Error("Expected the retired file to hold the entries before the change")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0001-01-01_1_trace.html")); err == nil {
+		t.Error("Expected no file named after the zero period")
+	}
+}
@@ -0,0 +1,124 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// slowScopeColor is the color of the exit lines of scopes slower than Config.SlowScope
+const slowScopeColor = "orange"
+
+// scopeDepths maps goroutine IDs to the number of scopes open on them
+var scopeDepths sync.Map
+
+// Scope writes an entry line for name and returns a function that writes the exit
+// line with the elapsed time, to be deferred:
+//
+//	defer tracer.Scope("SyncDevices", "devices", len(devices))()
+//
+// The lines of scopes nested in it on the same goroutine are indented one level
+// deeper. Other entries are not looked up, so they are not indented; use ScopeCtx
+// and TraceCtx to indent them. The exit line is orange when the scope took longer
+// than Config.SlowScope.
+func (t *Tracer) Scope(name string, args ...any) func() {
+	return t.scope(name, args, true)
+}
+
+// ScopeCtx writes an entry line for name like Scope, for scopes that span
+// goroutines. The entries traced with the returned context are indented one
+// level deeper, and the returned function writes the exit line:
+//
+//	ctx, done := tracer.ScopeCtx(ctx, "SyncDevices")
+//	defer done()
+func (t *Tracer) ScopeCtx(ctx context.Context, name string, args ...any) (context.Context, func()) {
+	values := valuesFrom(ctx)
+	values.depth++
+	return context.WithValue(ctx, contextKey{}, values), t.WithContext(ctx).scope(name, args, false)
+}
+
+// scope writes the entry line and returns the function writing the exit line.
+// The scope depth of the calling goroutine is tracked unless the context tracks it.
+func (t *Tracer) scope(name string, args []any, perGoroutine bool) func() {
+	if !t.Enabled(LevelInfo) {
+		return func() {}
+	}
+
+	fields := fieldsFromArgs(args)
+
+	// Only the scope lines are indented, so the goroutine is looked up once per scope
+	var id uint64
+	if perGoroutine {
+		id = goroutineID()
+		if depth := enterScope(id); depth > 0 {
+			nested := *t
+			nested.depth += depth
+			t = &nested
+		}
+	}
+
+	// The exit line reports the location of the entry line, looked up when it is written
+	var caller Caller
+	start := time.Now()
+	t.log(start, LevelInfo, message{text: name + " started"}, "", fields, func() Caller {
+		// Skip log, scope and the exported method calling it
+		caller = t.caller(4)
+		return caller
+	})
+
+	return func() {
+		if perGoroutine {
+			exitScope(id)
+		}
+		elapsed := time.Since(start)
+		color := ""
+		if slow := t.currentConfig().SlowScope; slow > 0 && elapsed >= slow {
+			color = slowScopeColor
+		}
+		msg := message{text: fmt.Sprintf("%s completed in %v", name, roundElapsed(elapsed))}
+		t.log(time.Now(), LevelInfo, msg, color, fields, func() Caller { return caller })
+	}
+}
+
+// enterScope adds a scope to the depth of a goroutine and returns the depth before it
+func enterScope(id uint64) int {
+	depth, _ := scopeDepths.Load(id)
+	n, _ := depth.(int)
+	scopeDepths.Store(id, n+1)
+	return n
+}
+
+// exitScope removes a scope from the depth of a goroutine
+func exitScope(id uint64) {
+	depth, _ := scopeDepths.Load(id)
+	if n, _ := depth.(int); n > 1 {
+		scopeDepths.Store(id, n-1)
+	} else {
+		scopeDepths.Delete(id)
+	}
+}
+
+// roundElapsed rounds a duration to milliseconds above a second and to microseconds
+// above a millisecond, e.g. 1.234s or 12.345ms
+func roundElapsed(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	}
+	return d
+}
+
+// Scope writes an entry line to the default trace log and returns the function
+// writing the exit line
+func Scope(name string, args ...any) func() {
+	return stdCaller.Scope(name, args...)
+}
+
+// ScopeCtx writes an entry line to the default trace log for a scope tracked by
+// the returned context
+func ScopeCtx(ctx context.Context, name string, args ...any) (context.Context, func()) {
+	return stdCaller.ScopeCtx(ctx, name, args...)
+}
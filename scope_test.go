@@ -0,0 +1,93 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestScope verifies the entry and exit lines and the indentation of nested scopes,
+// which leaves the other entries of the goroutine as they are
+func TestScope(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	func() {
+		defer tr.Scope("SyncDevices", "devices", 2)()
+		tr.Trace("Inside")
+		func() {
+			defer tr.Scope("Device")()
+		}()
+	}()
+	tr.Trace("Outside")
+
+	output := buf.String()
+	for _, expected := range []string{
+		"INFO  - SyncDevices started devices=2\n",
+		"INFO  - Inside\n",
+		"INFO  -   Device started\n",
+		"INFO  -   Device completed in ",
+		"INFO  - SyncDevices completed in ",
+		"INFO  - Outside\n",
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected '%s', got:\n%s", expected, output)
+		}
+	}
+	if _, open := scopeDepths.Load(goroutineID()); open {
+		t.Error("Expected the scopes to be closed")
+	}
+}
+
+// TestSlowScope verifies that the exit line of a slow scope is colored
+func TestSlowScope(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{SlowScope: 10 * time.Millisecond, Sinks: []Sink{{Writer: &buf, Encoder: JSONEncoder{}}}})
+
+	func() {
+		defer tr.Scope("Fast")()
+	}()
+	func() {
+		defer tr.Scope("Slow")()
+		time.Sleep(20 * time.Millisecond)
+	}()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got:\n%s", buf.String())
+	}
+	if !contains(lines[1], `"color":"white"`) || !contains(lines[1], "Fast completed") {
+		t.Errorf("Expected a white exit line, got '%s'", lines[1])
+	}
+	if !contains(lines[3], `"color":"orange"`) || !contains(lines[3], "Slow completed") {
+		t.Errorf("Expected an orange exit line, got '%s'", lines[3])
+	}
+}
+
+// TestScopeCtx verifies that context scopes indent the entries traced with the context
+func TestScopeCtx(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	ctx, done := tr.ScopeCtx(context.Background(), "Request", "request_id", "r-1")
+	finished := make(chan struct{})
+	go func() {
+		tr.TraceCtx(ctx, "Worker")
+		close(finished)
+	}()
+	<-finished
+	done()
+
+	output := buf.String()
+	for _, expected := range []string{
+		"INFO  - Request started request_id=r-1\n",
+		"INFO  -   Worker\n",
+		"INFO  - Request completed in ",
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected '%s', got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package tracer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting is one key/value pair of a config or marker file
+type setting struct {
+	key   string
+	value string
+}
+
+// fileSettings are the settings read from a file, applied on top of the Config
+type fileSettings []setting
+
+// apply overrides the fields of cfg given in the settings
+func (s fileSettings) apply(cfg *Config) {
+	for _, setting := range s {
+		// The settings were validated when they were parsed
+		_ = applySetting(cfg, setting.key, setting.value)
+	}
+}
+
+// parseSettings parses one key=value setting per line, as found in marker files
+// and INI-style config files. "key: value" is accepted too. Empty lines, [section]
+// headers and lines starting with # or ; are skipped. Invalid settings are reported
+// together, and the valid ones are still returned.
+func parseSettings(data []byte) (fileSettings, error) {
+	var settings fileSettings
+	var errs []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' || line[0] == '[' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%q is not key=value", line))
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		settings, errs = appendSetting(settings, errs, key, value)
+	}
+
+	return settings, joinErrors(errs)
+}
+
+// parseJSONSettings parses a JSON object of settings. Values may be strings,
+// numbers or booleans.
+func parseJSONSettings(data []byte) (fileSettings, error) {
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	var settings fileSettings
+	var errs []string
+	for key, value := range object {
+		switch value := value.(type) {
+		case string:
+			settings, errs = appendSetting(settings, errs, key, value)
+		case float64:
+			settings, errs = appendSetting(settings, errs, key, strconv.FormatFloat(value, 'f', -1, 64))
+		case bool:
+			settings, errs = appendSetting(settings, errs, key, strconv.FormatBool(value))
+		default:
+			errs = append(errs, fmt.Sprintf("%s: value must be a string, number or boolean", key))
+		}
+	}
+
+	return settings, joinErrors(errs)
+}
+
+// appendSetting validates the setting and appends it, or its error
+func appendSetting(settings fileSettings, errs []string, key, value string) (fileSettings, []string) {
+	key = normalizeKey(key)
+
+	var scratch Config
+	if err := applySetting(&scratch, key, value); err != nil {
+		return settings, append(errs, fmt.Sprintf("%s: %v", key, err))
+	}
+	return append(settings, setting{key: key, value: value}), errs
+}
+
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// normalizeKey lower-cases the key and drops separators, so max_size, max-size
+// and MaxSize are the same setting
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(key)))
+}
+
+// applySetting sets the Config field named by the normalized key
+func applySetting(cfg *Config, key, value string) error {
+	switch key {
+	case "enabled", "enable":
+		if strings.EqualFold(value, "auto") {
+			cfg.Enabled = EnableAuto
+			return nil
+		}
+		enabled, ok := parseEnable(value)
+		if !ok {
+			return fmt.Errorf("invalid value %q, expected on, off or auto", value)
+		}
+		cfg.Enabled = EnableOff
+		if enabled {
+			cfg.Enabled = EnableOn
+		}
+	case "level", "minlevel":
+		level, err := ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		cfg.MinLevel = level
+	case "maxsize":
+		size, err := parseSize(value)
+		if err != nil {
+			return err
+		}
+		cfg.MaxSize = size
+	case "maxfiles":
+		files, err := strconv.Atoi(value)
+		if err != nil || files <= 0 {
+			return fmt.Errorf("invalid file count %q", value)
+		}
+		cfg.MaxFiles = files
+	case "maxage":
+		age, err := parseAge(value)
+		if err != nil {
+			return err
+		}
+		cfg.MaxAge = age
+	case "maxtotalbytes", "maxtotalsize":
+		size, err := parseSize(value)
+		if err != nil {
+			return err
+		}
+		cfg.MaxTotalBytes = size
+	case "rotation":
+		rotation, err := parseRotation(value)
+		if err != nil {
+			return err
+		}
+		cfg.Rotation = rotation
+	case "compress":
+		compress, ok := parseEnable(value)
+		if !ok {
+			return fmt.Errorf("invalid value %q, expected true or false", value)
+		}
+		cfg.Compress = compress
+	case "userid", "user":
+		cfg.UserID = value
+	case "dir":
+		cfg.Dir = value
+	case "filename":
+		cfg.FileName = value
+	case "rotatedname":
+		cfg.RotatedName = value
+	case "modules":
+		if _, err := parseModules(value); err != nil {
+			return err
+		}
+		cfg.Modules = value
+	case "slowscope":
+		threshold, err := time.ParseDuration(value)
+		if err != nil || threshold <= 0 {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		cfg.SlowScope = threshold
+	default:
+		return fmt.Errorf("unknown setting")
+	}
+	return nil
+}
+
+// parseSize parses a byte count such as 500000, 512KB, 10MB or 1GB.
+// Units are decimal, like the 5MB default of MaxSize.
+func parseSize(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1_000_000_000}, {"G", 1_000_000_000},
+		{"MB", 1_000_000}, {"M", 1_000_000},
+		{"KB", 1_000}, {"K", 1_000},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(number, unit.suffix) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return size * multiplier, nil
+}
+
+// parseAge parses a duration such as 36h, or a number of days such as 7d
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return age, nil
+}
+
+// parseRotation parses a policy such as "daily" or "size|hourly"
+func parseRotation(value string) (RotationPolicy, error) {
+	var rotation RotationPolicy
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' || r == '+' }) {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "size":
+			rotation |= RotateBySize
+		case "hourly":
+			rotation |= RotateHourly
+		case "daily":
+			rotation |= RotateDaily
+		default:
+			return 0, fmt.Errorf("unknown rotation %q", name)
+		}
+	}
+	if rotation == 0 {
+		return 0, fmt.Errorf("invalid rotation %q", value)
+	}
+	return rotation, nil
+}
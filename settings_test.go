@@ -0,0 +1,101 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestParseSettings verifies parsing of the marker file contents
+func TestParseSettings(t *testing.T) {
+	data := []byte("# raised for ticket 4711\nlevel=debug\nmaxsize = 10MB\nmaxfiles=30\nmodules=poller,http\n\n")
+	settings, err := parseSettings(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg := defaultConfig
+	cfg.MinLevel = LevelWarn
+	settings.apply(&cfg)
+	if cfg.MinLevel != LevelDebug || cfg.MaxSize != 10_000_000 || cfg.MaxFiles != 30 || cfg.Modules != "poller,http" {
+		t.Errorf("Expected debug/10MB/30/poller,http, got %v/%d/%d/%s", cfg.MinLevel, cfg.MaxSize, cfg.MaxFiles, cfg.Modules)
+	}
+
+	settings, err = parseSettings([]byte("level=loud\nmaxfiles=5\ncolor"))
+	if err == nil {
+		t.Error("Expected an error for the invalid settings")
+	}
+	cfg = defaultConfig
+	cfg.MinLevel = LevelWarn
+	settings.apply(&cfg)
+	if cfg.MaxFiles != 5 || cfg.MinLevel != LevelWarn {
+		t.Error("Expected the valid settings to be kept")
+	}
+}
+
+// TestParseSize verifies byte counts with units
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+	}{
+		{"500000", 500_000},
+		{"512KB", 512_000},
+		{"10MB", 10_000_000},
+		{"10 mb", 10_000_000},
+		{"1G", 1_000_000_000},
+	}
+
+	for _, tt := range tests {
+		size, err := parseSize(tt.value)
+		if err != nil || size != tt.expected {
+			t.Errorf("parseSize(%q) = %d, %v, expected %d", tt.value, size, err, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"", "MB", "-1", "ten"} {
+		if _, err := parseSize(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+// TestSettingsReloadOnChange verifies that edits to the marker file are picked up
+func TestSettingsReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "TraceEnable.txt")
+	if err := os.WriteFile(marker, []byte("level=warn\n"), 0644); err != nil {
+		t.Fatalf("Failed to create marker file: %v", err)
+	}
+
+	tr := New(Config{ExecutableName: "TestSettings", EnableDirs: []string{dir}, MinLevel: LevelInfo})
+	if tr.Enabled(LevelInfo) {
+		t.Error("Expected info entries to be dropped at level=warn")
+	}
+
+	if err := os.WriteFile(marker, []byte("level=debug\nmaxsize=1MB\n"), 0644); err != nil {
+		t.Fatalf("Failed to update marker file: %v", err)
+	}
+	modTime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(marker, modTime, modTime); err != nil {
+		t.Fatalf("Failed to touch marker file: %v", err)
+	}
+
+	// Skip the wait for the next periodic check
+	tr.refreshEnabled()
+	if !tr.Enabled(LevelDebug) {
+		t.Error("Expected debug entries after raising the level")
+	}
+	if cfg := tr.currentConfig(); cfg.MaxSize != 1_000_000 {
+		t.Errorf("Expected MaxSize 1MB from the marker file, got %d", cfg.MaxSize)
+	}
+
+	if err := os.Remove(marker); err != nil {
+		t.Fatalf("Failed to remove marker file: %v", err)
+	}
+	tr.refreshEnabled()
+	if tr.Enabled(LevelDebug) {
+		t.Error("Expected the marker file settings to be dropped with the file")
+	}
+}
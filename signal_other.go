@@ -0,0 +1,8 @@
+//go:build !unix
+
+package tracer
+
+import "os"
+
+// notifyReload does nothing, there is no SIGHUP on this platform
+func notifyReload(ch chan<- os.Signal) {}
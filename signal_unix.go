@@ -0,0 +1,14 @@
+//go:build unix
+
+package tracer
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReload relays SIGHUP to ch
+func notifyReload(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGHUP)
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestSIGHUPReopensLogFile verifies that SIGHUP reopens a log file moved away by logrotate
+func TestSIGHUPReopensLogFile(t *testing.T) {
+	dir := t.TempDir()
+	tr := New(Config{ExecutableName: "TestSIGHUP", Dir: dir, Enabled: EnableOn, DisableStdout: true})
+	defer tr.Close()
+
+	stop := tr.HandleSIGHUP()
+	defer stop()
+
+	tr.Trace("Before logrotate")
+	logFilename := filepath.Join(dir, "trace.html")
+	if err := os.Rename(logFilename, logFilename+".1"); err != nil {
+		t.Fatalf("Failed to move log file: %v", err)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("Failed to send SIGHUP: %v", err)
+	}
+
+	// The handler runs asynchronously, well before the periodic reopen check
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		tr.Trace("After logrotate")
+		if _, err := os.Stat(logFilename); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected the log file to be reopened after SIGHUP")
+}
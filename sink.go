@@ -0,0 +1,174 @@
+package tracer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sink is a destination for trace entries with its own format and level
+type Sink struct {
+	// Writer receives the encoded entries. When nil, the sink writes to a
+	// rotating trace.<ext> file in the trace folder, which is only written
+	// while tracing is enabled.
+	Writer io.Writer
+	// Encoder renders the entries (default: HTMLEncoder for files,
+	// the message and its fields for writers)
+	Encoder Encoder
+	// MinLevel drops entries below this level for this sink only
+	MinLevel Level
+	// Filter drops the entries for which it returns false
+	Filter func(*Entry) bool
+}
+
+// StdoutSink returns the sink that prints the message and fields of every entry to stdout
+func StdoutSink() Sink {
+	return Sink{Writer: os.Stdout, Encoder: consoleEncoder{}}
+}
+
+// FileSink returns a sink that writes to a rotating trace.<ext> file in the trace folder
+func FileSink(encoder Encoder) Sink {
+	return Sink{Encoder: encoder}
+}
+
+// accepts reports whether the sink wants the entry
+func (s *Sink) accepts(entry *Entry) bool {
+	if entry.Level < s.MinLevel {
+		return false
+	}
+	return s.Filter == nil || s.Filter(entry)
+}
+
+// consoleEncoder prints the message and its fields, one entry per line
+type consoleEncoder struct{}
+
+func (consoleEncoder) Extension() string {
+	return "txt"
+}
+
+func (consoleEncoder) Header() []byte {
+	return nil
+}
+
+func (consoleEncoder) Footer() []byte {
+	return nil
+}
+
+func (consoleEncoder) Encode(dst []byte, entry *Entry) []byte {
+	dst = appendIndent(dst, entry, "  ")
+	dst = appendModule(dst, entry)
+	dst = append(dst, entry.Text()...)
+	dst = append(dst, formatTextFields(entry.Fields)...)
+	return append(dst, '\n')
+}
+
+// sinks returns the configured sinks, or the default stdout and file sinks
+func (cfg *Config) sinks() []Sink {
+	if len(cfg.Sinks) > 0 {
+		return cfg.Sinks
+	}
+	sinks := make([]Sink, 0, 2)
+	if !cfg.DisableStdout {
+		sinks = append(sinks, StdoutSink())
+	}
+	return append(sinks, FileSink(cfg.Encoder))
+}
+
+// writeEntries sends entries to every sink, with a single write per sink
+func (c *core) writeEntries(entries []*Entry, cfg Config) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	enabledChecked, enabled := false, false
+	batch := make([]*Entry, 0, len(entries))
+
+	sinks := cfg.sinks()
+	for i := range sinks {
+		sink := &sinks[i]
+
+		batch = batch[:0]
+		for _, entry := range entries {
+			if sink.accepts(entry) {
+				batch = append(batch, entry)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		encoder := sink.Encoder
+		if sink.Writer != nil {
+			if encoder == nil {
+				encoder = consoleEncoder{}
+			}
+			var data []byte
+			for _, entry := range batch {
+				data = encoder.Encode(data, entry)
+			}
+			if _, err := sink.Writer.Write(data); err != nil {
+				fmt.Printf("Error writing to sink: %v\n", err)
+			}
+			continue
+		}
+
+		if !enabledChecked {
+			enabled, enabledChecked = c.traceEnabled(&cfg), true
+		}
+		if !enabled {
+			continue
+		}
+		if encoder == nil {
+			encoder = HTMLEncoder{}
+		}
+		c.writeFile(encoder, batch, cfg)
+	}
+}
+
+// writeFile appends entries to the log file of the encoder with a single write
+func (c *core) writeFile(encoder Encoder, entries []*Entry, cfg Config) {
+	fileName, rotatedName := cfg.nameTemplates(encoder.Extension())
+	logFilename := filepath.Join(cfg.logDir(), fileName)
+
+	// Keep one log file open per file name across calls
+	logFile := c.logFiles[logFilename]
+	if logFile == nil {
+		if c.logFiles == nil {
+			c.logFiles = make(map[string]*LogFile)
+		}
+		logFile = newLogFile(logFilename, encoder.Header(), encoder.Footer(), cfg.MaxSize, cfg.Rotation)
+		logFile.rotated = func(retired string) {
+			c.afterRotation(logFilename, retired)
+		}
+		c.logFiles[logFilename] = logFile
+		c.startSweep()
+	}
+	logFile.configure(cfg.MaxSize, cfg.Rotation, cfg.MultiProcess, rotatedName)
+
+	var logEntry []byte
+	for _, entry := range entries {
+		logEntry = encoder.Encode(logEntry, entry)
+	}
+
+	if err := logFile.write(logEntry); err != nil {
+		// Start over with a fresh file, retiring the failing one like any rotation
+		if err := logFile.forceRotate(); err != nil {
+			fmt.Printf("Error rotating log file: %v\n", err)
+			return
+		}
+		if err := logFile.write(logEntry); err != nil {
+			fmt.Printf("Error writing log file: %v\n", err)
+		}
+	}
+}
+
+// closeLogFile closes the log file handles kept open by the tracer
+func (c *core) closeLogFile() {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	for name, logFile := range c.logFiles {
+		logFile.close()
+		delete(c.logFiles, name)
+	}
+}
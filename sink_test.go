@@ -0,0 +1,76 @@
+package tracer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDefaultSinks verifies the default stdout and file sinks and turning stdout off
+func TestDefaultSinks(t *testing.T) {
+	cfg := Config{Encoder: JSONEncoder{}}
+	sinks := cfg.sinks()
+	if len(sinks) != 2 || sinks[0].Writer != os.Stdout || sinks[1].Writer != nil {
+		t.Fatalf("Expected stdout and file sinks, got %+v", sinks)
+	}
+	if sinks[1].Encoder != (JSONEncoder{}) {
+		t.Error("Expected the file sink to use the configured encoder")
+	}
+
+	cfg.DisableStdout = true
+	sinks = cfg.sinks()
+	if len(sinks) != 1 || sinks[0].Writer != nil {
+		t.Errorf("Expected only the file sink, got %+v", sinks)
+	}
+}
+
+// TestMultipleSinks verifies that each sink gets the entries with its own level and format
+func TestMultipleSinks(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	var console, errors bytes.Buffer
+	tr := New(Config{
+		ExecutableName: "TestSinks",
+		Sinks: []Sink{
+			FileSink(HTMLEncoder{}),
+			FileSink(JSONEncoder{}),
+			{Writer: &console, Encoder: TextEncoder{}},
+			{Writer: &errors, MinLevel: LevelError},
+			{Writer: &errors, Filter: func(e *Entry) bool { return strings.Contains(e.Message, "door") }},
+		},
+	})
+	folderName := "Trace TestSinks"
+	defer os.RemoveAll(folderName)
+	defer tr.Close()
+
+	tr.TraceFields("Polling", "device", 2)
+	tr.Error("Device offline")
+	tr.Trace("Front door opened")
+
+	htmlContent, err := os.ReadFile(filepath.Join(folderName, "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read HTML log file: %v", err)
+	}
+	jsonContent, err := os.ReadFile(filepath.Join(folderName, "trace.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read JSON log file: %v", err)
+	}
+
+	for _, content := range []string{string(htmlContent), string(jsonContent), console.String()} {
+		if !contains(content, "Polling") || !contains(content, "Device offline") {
+			t.Errorf("Expected every entry in '%s'", content)
+		}
+	}
+	if !contains(console.String(), "INFO  - Polling device=2") {
+		t.Errorf("Expected text format on the console sink, got '%s'", console.String())
+	}
+	if errors.String() != "** Device offline\nFront door opened\n" {
+		t.Errorf("Expected only the error and the filtered entry, got '%s'", errors.String())
+	}
+}
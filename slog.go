@@ -0,0 +1,127 @@
+package tracer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHandlerOptions configures the handler returned by NewSlogHandler
+type SlogHandlerOptions struct {
+	// Tracer receives the records (default: the default tracer)
+	Tracer *Tracer
+	// Level is the minimum slog level handled, on top of the tracer MinLevel
+	// (default: slog.LevelDebug)
+	Level slog.Leveler
+}
+
+// SlogHandler is a slog.Handler that writes records as tracer entries,
+// through the same sinks, rotation and retention as Trace
+type SlogHandler struct {
+	tracer *Tracer
+	level  slog.Leveler
+	fields []Field
+	prefix string
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// NewSlogHandler creates a slog.Handler backed by a tracer, so that
+//
+//	slog.SetDefault(slog.New(tracer.NewSlogHandler(nil)))
+//
+// sends every slog record to the familiar trace.html.
+// Attributes and groups become fields, with group names joined by dots.
+func NewSlogHandler(opts *SlogHandlerOptions) *SlogHandler {
+	h := &SlogHandler{tracer: std, level: slog.LevelDebug}
+	if opts != nil {
+		if opts.Tracer != nil {
+			h.tracer = opts.Tracer
+		}
+		if opts.Level != nil {
+			h.level = opts.Level
+		}
+	}
+	return h
+}
+
+// slogLevel maps a slog level to the tracer level, and so to its color
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	}
+	return LevelError
+}
+
+// Enabled reports whether records of the given level are written
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.tracer.Enabled(slogLevel(level))
+}
+
+// Handle writes the record as a tracer entry, with the user ID, fields and
+// goroutine name carried by ctx
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, 0, len(h.fields)+r.NumAttrs())
+	fields = append(fields, h.fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	tracer := h.tracer.WithContext(ctx)
+	tracer.log(now, slogLevel(r.Level), message{text: r.Message}, "", fields, func() Caller { return tracer.callerAt(r.PC) })
+	return nil
+}
+
+// WithAttrs returns a handler that adds the attributes to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	child := *h
+	child.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, a := range attrs {
+		child.fields = appendAttr(child.fields, h.prefix, a)
+	}
+	return &child
+}
+
+// WithGroup returns a handler that qualifies the keys of later attributes with the group name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.prefix = h.prefix + name + "."
+	return &child
+}
+
+// appendAttr appends the attribute as fields, flattening groups into dotted keys
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
@@ -0,0 +1,72 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// TestSlogLevelMapping verifies that slog levels map to tracer levels
+func TestSlogLevelMapping(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected Level
+	}{
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 4, LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := slogLevel(tt.level); got != tt.expected {
+			t.Errorf("Expected %v to map to %v, got %v", tt.level, tt.expected, got)
+		}
+	}
+}
+
+// TestSlogHandler verifies that records, attributes and groups become tracer entries
+func TestSlogHandler(t *testing.T) {
+	var out bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &out, Encoder: HTMLEncoder{}}}})
+
+	logger := slog.New(NewSlogHandler(&SlogHandlerOptions{Tracer: tr, Level: slog.LevelInfo}))
+
+	logger.Debug("hidden")
+	logger.With("device", 2).WithGroup("req").Warn("Slow <reply>", "ms", 250, slog.Group("peer", "port", 4370))
+	logger.Error("Failed", slog.Group("", "code", 7), slog.Group("empty"))
+
+	expected := []string{
+		`<font color="yellow">`,
+		`Slow &lt;reply&gt; <span class="field">device=2</span> <span class="field">req.ms=250</span> <span class="field">req.peer.port=4370</span>`,
+		`<font color="red">`,
+		`Failed <span class="field">code=7</span>`,
+	}
+	for _, e := range expected {
+		if !contains(out.String(), e) {
+			t.Errorf("Expected output to contain '%s', got '%s'", e, out.String())
+		}
+	}
+	if contains(out.String(), "hidden") {
+		t.Error("Expected records below the handler level to be dropped")
+	}
+	if contains(out.String(), "empty") {
+		t.Error("Expected empty groups to be dropped")
+	}
+}
+
+// TestSlogHandlerMinLevel verifies that the tracer MinLevel also applies to slog records
+func TestSlogHandlerMinLevel(t *testing.T) {
+	tr := New(Config{MinLevel: LevelError, DisableStdout: true})
+	h := NewSlogHandler(&SlogHandlerOptions{Tracer: tr})
+
+	if h.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("Expected warnings to be disabled below the tracer MinLevel")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected errors to be enabled")
+	}
+}
@@ -0,0 +1,183 @@
+package tracer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// TraceparentHeader is the HTTP header carrying the span of a request, as defined
+// by W3C Trace Context
+const TraceparentHeader = "traceparent"
+
+// FlagSampled is the trace flag telling that the caller may record the trace
+const FlagSampled byte = 0x01
+
+// TraceID identifies a trace, the spans of one request across services
+type TraceID [16]byte
+
+// String returns the ID as 32 lowercase hex digits
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within its trace
+type SpanID [8]byte
+
+// String returns the ID as 16 lowercase hex digits
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span, as carried by a traceparent header
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   byte
+}
+
+// IsValid reports whether the trace and span IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent returns the span as a version 00 traceparent header value,
+// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses a traceparent header value. Versions above 00 are
+// accepted as long as they start with the fields of version 00.
+func ParseTraceparent(header string) (SpanContext, error) {
+	var sc SpanContext
+	invalid := fmt.Errorf("invalid traceparent %q", header)
+
+	if len(header) < 55 || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return sc, invalid
+	}
+	version, ok := parseHex(header[:2])
+	if !ok || version[0] == 0xff || (version[0] == 0 && len(header) != 55) || (len(header) > 55 && header[55] != '-') {
+		return sc, invalid
+	}
+	traceID, ok := parseHex(header[3:35])
+	if !ok {
+		return sc, invalid
+	}
+	spanID, ok := parseHex(header[36:52])
+	if !ok {
+		return sc, invalid
+	}
+	flags, ok := parseHex(header[53:55])
+	if !ok {
+		return sc, invalid
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, invalid
+	}
+	return sc, nil
+}
+
+// parseHex decodes lowercase hex digits, the only case traceparent allows
+func parseHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// WithSpanContext returns a context carrying a span, such as one parsed from the
+// traceparent header of an incoming request. Entries traced with it are stamped
+// with its trace and span IDs, and StartSpan makes its spans children of it.
+func WithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	values := valuesFrom(ctx)
+	values.span = sc
+	return context.WithValue(ctx, contextKey{}, values)
+}
+
+// SpanFromContext returns the span carried by ctx, or the zero SpanContext
+func SpanFromContext(ctx context.Context) SpanContext {
+	return valuesFrom(ctx).span
+}
+
+// Span is a timed operation within a trace, started with StartSpan
+type Span struct {
+	SpanContext
+	// Parent is the span the span was started in, zero for the root of a trace
+	Parent SpanID
+	Name   string
+	end    func()
+}
+
+// End writes the exit line of the span with its elapsed time
+func (s *Span) End() {
+	s.end()
+}
+
+// StartSpan starts a span named name as a child of the span carried by ctx, or as
+// the root of a new trace. Like ScopeCtx, it writes an entry line, the entries
+// traced with the returned context are indented and stamped with the trace and span
+// IDs, and End writes the exit line:
+//
+//	ctx, span := tracer.StartSpan(ctx, "SyncDevices")
+//	defer span.End()
+//	req.Header.Set(tracer.TraceparentHeader, span.Traceparent())
+func (t *Tracer) StartSpan(ctx context.Context, name string, args ...any) (context.Context, *Span) {
+	values := valuesFrom(ctx)
+	span := &Span{Name: name, Parent: values.span.SpanID}
+	if values.span.IsValid() {
+		span.TraceID = values.span.TraceID
+		span.Flags = values.span.Flags
+	} else {
+		span.TraceID = newTraceID()
+		span.Flags = FlagSampled
+	}
+	span.SpanID = newSpanID()
+
+	values.span = span.SpanContext
+	inner := context.WithValue(ctx, contextKey{}, values)
+	values.depth++
+	span.end = t.WithContext(inner).scope(name, args, false)
+	return context.WithValue(ctx, contextKey{}, values), span
+}
+
+// newTraceID returns a random trace ID
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// newSpanID returns a random span ID
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// StartSpan starts a span on the default tracer
+func StartSpan(ctx context.Context, name string, args ...any) (context.Context, *Span) {
+	return stdCaller.StartSpan(ctx, name, args...)
+}
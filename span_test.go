@@ -0,0 +1,100 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestParseTraceparent verifies the traceparent headers accepted and rejected
+func TestParseTraceparent(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceparent(header)
+	if err != nil {
+		t.Fatalf("Expected a valid header, got %v", err)
+	}
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || sc.Flags != FlagSampled {
+		t.Errorf("Unexpected span %+v", sc)
+	}
+	if sc.Traceparent() != header {
+		t.Errorf("Expected '%s', got '%s'", header, sc.Traceparent())
+	}
+
+	if _, err := ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future"); err != nil {
+		t.Errorf("Expected a later version to be accepted, got %v", err)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+	} {
+		if _, err := ParseTraceparent(invalid); err == nil {
+			t.Errorf("Expected '%s' to be rejected", invalid)
+		}
+	}
+}
+
+// TestStartSpan verifies that spans share the trace of their parent and stamp their entries
+func TestStartSpan(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &buf, Encoder: TextEncoder{}}}})
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx, parent := tr.StartSpan(WithSpanContext(context.Background(), remote), "Request")
+	childCtx, child := tr.StartSpan(ctx, "Query")
+	tr.TraceCtx(childCtx, "Rows", 3)
+	child.End()
+	parent.End()
+
+	if parent.TraceID != remote.TraceID || parent.Parent != remote.SpanID || parent.Flags != 0 {
+		t.Errorf("Expected the span to continue the remote trace, got %+v", parent)
+	}
+	if child.TraceID != parent.TraceID || child.Parent != parent.SpanID || child.SpanID == parent.SpanID {
+		t.Errorf("Expected a child of the span, got %+v", child)
+	}
+	if SpanFromContext(childCtx) != child.SpanContext {
+		t.Error("Expected the child span in its context")
+	}
+
+	output := buf.String()
+	traceID := remote.TraceID.String()
+	for _, expected := range []string{
+		fmt.Sprintf("INFO  - Request started trace_id=%s span_id=%s\n", traceID, parent.SpanID),
+		fmt.Sprintf("INFO  -   Query started trace_id=%s span_id=%s\n", traceID, child.SpanID),
+		fmt.Sprintf("INFO  -     Rows 3 trace_id=%s span_id=%s\n", traceID, child.SpanID),
+		"INFO  - Request completed in ",
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected '%s', got:\n%s", expected, output)
+		}
+	}
+
+	_, root := tr.StartSpan(context.Background(), "Root")
+	root.End()
+	if !root.IsValid() || root.TraceID == remote.TraceID || root.Parent.IsValid() || root.Flags != FlagSampled {
+		t.Errorf("Expected the root of a new trace, got %+v", root)
+	}
+}
+
+// TestHTMLTraceFilter verifies the one-click trace filter of the HTML entries
+func TestHTMLTraceFilter(t *testing.T) {
+	sc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	entry := &Entry{Message: "Request", Color: "white", TraceID: sc.TraceID.String(), SpanID: sc.SpanID.String()}
+	output := string(HTMLEncoder{}.Encode(nil, entry))
+
+	expected := `<span class="trace" title="Filter by trace" onclick="filter_trace('4bf92f3577b34da6a3ce929d0e0e4736')">` +
+		`trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7</span>`
+	if !contains(output, expected) {
+		t.Errorf("Expected '%s', got '%s'", expected, output)
+	}
+	if !contains(htmlPageHeader, "function filter_trace(id)") {
+		t.Error("Expected the filter_trace function in the page header")
+	}
+}
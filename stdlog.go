@@ -0,0 +1,86 @@
+package tracer
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// lineWriter turns every line written to it into a tracer entry
+type lineWriter struct {
+	tracer *Tracer
+	color  string
+	mutex  sync.Mutex
+	buf    []byte
+}
+
+// Write logs every complete line of p as a tracer entry.
+// A trailing partial line is kept until its newline arrives.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// Look the caller of Write up once, for the first line written
+	var resolved *Caller
+	caller := func() Caller {
+		if resolved == nil {
+			// Skip log and Write
+			c := w.tracer.caller(3)
+			resolved = &c
+		}
+		return *resolved
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.buf[:i], []byte("\r"))
+		w.tracer.log(time.Now(), LevelInfo, message{text: string(line)}, w.color, nil, caller)
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Writer returns an io.Writer that writes every line as a tracer entry with the given color.
+// An empty color selects the default info color.
+func (t *Tracer) Writer(color string) io.Writer {
+	return &lineWriter{tracer: t, color: color}
+}
+
+// RedirectStdLog sends the output of the standard log package to the tracer.
+// The std-log prefix and flags are cleared so the timestamp is not duplicated.
+// It returns a function that restores the previous output, prefix and flags:
+//
+//	defer tracer.RedirectStdLog()()
+func (t *Tracer) RedirectStdLog() func() {
+	logger := log.Default()
+	output, prefix, flags := logger.Writer(), logger.Prefix(), logger.Flags()
+
+	// Report the caller of log.Println and friends, past the log package
+	logger.SetOutput(t.AddCallerSkip(2).Writer(""))
+	logger.SetPrefix("")
+	logger.SetFlags(0)
+
+	return func() {
+		logger.SetOutput(output)
+		logger.SetPrefix(prefix)
+		logger.SetFlags(flags)
+	}
+}
+
+// Writer returns an io.Writer that writes every line to the default trace log with the given color
+func Writer(color string) io.Writer {
+	return std.Writer(color)
+}
+
+// RedirectStdLog sends the output of the standard log package to the default trace log
+func RedirectStdLog() func() {
+	return std.RedirectStdLog()
+}
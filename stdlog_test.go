@@ -0,0 +1,46 @@
+package tracer
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+// TestWriterLines verifies that every complete line becomes one entry
+func TestWriterLines(t *testing.T) {
+	var out bytes.Buffer
+	tr := New(Config{Sinks: []Sink{{Writer: &out}}})
+	w := tr.Writer("cyan")
+
+	w.Write([]byte("first line\nsecond "))
+	if out.String() != "first line\n" {
+		t.Errorf("Expected only the complete line, got '%s'", out.String())
+	}
+
+	w.Write([]byte("line\r\n"))
+	if out.String() != "first line\nsecond line\n" {
+		t.Errorf("Expected the partial line to be completed, got '%s'", out.String())
+	}
+}
+
+// TestRedirectStdLog verifies that std log output becomes tracer entries without its own prefix
+func TestRedirectStdLog(t *testing.T) {
+	var out bytes.Buffer
+	tr := New(Config{UserID: "Lib", Sinks: []Sink{{Writer: &out, Encoder: HTMLEncoder{}}}})
+
+	log.SetPrefix("lib: ")
+	restore := tr.RedirectStdLog()
+	log.Printf("Connected to %s", "db")
+	restore()
+
+	if !contains(out.String(), "Lib - Connected to db") {
+		t.Errorf("Expected std log line as a tracer entry, got '%s'", out.String())
+	}
+	if contains(out.String(), "lib: ") {
+		t.Error("Expected std log prefix to be stripped")
+	}
+	if log.Prefix() != "lib: " || log.Flags() != log.LstdFlags {
+		t.Error("Expected restore to bring back the previous prefix and flags")
+	}
+	log.SetPrefix("")
+}
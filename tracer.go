@@ -3,21 +3,16 @@ package tracer
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime/debug"
-	"sort"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	logMaxSize = 5_000_000 // 5MB
-	maxFiles   = 15
-)
-
-var (
-	globalMutex sync.Mutex
+	logMaxSize      = 5_000_000 // 5MB
+	maxFiles        = 15
+	asyncBufferSize = 1024
 )
 
 const htmlPageHeader = `<!DOCTYPE html>
@@ -46,6 +41,12 @@ function filter_log()
     document.body.style.cursor = 'default';
 }
 
+function filter_trace(id)
+{
+    filter = (filter == 'trace_id=' + id) ? '' : 'trace_id=' + id;
+    filter_log();
+}
+
 document.onkeydown = function(event) {
     if (event.keyCode == 76) {
         var ret = prompt("Enter the filter regular expression. Examples:\\n\\n\
@@ -66,19 +67,29 @@ BODY
   background-color:black;
   font-family:monospace, sans-serif;
 }
+SPAN.field
+{
+  color:silver;
+}
+SPAN.caller
+{
+  color:gray;
+}
+SPAN.trace
+{
+  color:gray;
+  cursor:pointer;
+}
 -->
 </STYLE>
 <body bgcolor="black" text="white">
 <font color="white">`
 
-// LogFile represents a log file with rotation capabilities
-type LogFile struct {
-	filename    string
-	maxSize     int64
-	currentSize int64
-	file        *os.File
-	mutex       sync.Mutex
-}
+const htmlPageFooter = `
+</font>
+</body>
+</html>
+`
 
 // Config holds the tracer configuration
 type Config struct {
@@ -86,6 +97,104 @@ type Config struct {
 	UserID         string
 	MaxSize        int64
 	MaxFiles       int
+	// Dir is the folder of the log files, absolute or relative to the executable
+	// (default: "Trace <ExecutableName>" in the working directory)
+	Dir string
+	// DirEnv names an environment variable holding the folder, which takes
+	// precedence over Dir when it is set
+	DirEnv string
+	// UseStateDir puts the logs in $XDG_STATE_HOME/<ExecutableName>
+	// (or ~/.local/state/<ExecutableName>) when no other folder is given
+	UseStateDir bool
+	// FileName is the name of the current log file (default: "trace.{ext}").
+	// It may use the {name}, {ext} and {pid} placeholders.
+	FileName string
+	// RotatedName is the name of a rotated log file (default: "{date}_{seq}_{file}").
+	// It may use {date}, {seq}, {file}, {name}, {ext} and {pid}.
+	RotatedName string
+	// Rotation selects when the log file is rotated (default: RotateBySize)
+	Rotation RotationPolicy
+	// Compress gzips rotated files in the background, e.g. to trace.html.gz
+	Compress bool
+	// MaxAge removes log files older than this (default: no age limit)
+	MaxAge time.Duration
+	// MaxTotalBytes removes the oldest log files while the files of a format
+	// take more than this many bytes together (default: no size limit)
+	MaxTotalBytes int64
+	// OnRemove is called with the name of every log file removed by retention
+	OnRemove func(filename string)
+	// MultiProcess takes an advisory file lock around every write and rotation,
+	// for executables that share the same trace folder
+	MultiProcess bool
+	// MinLevel drops entries below this level before any output is done
+	MinLevel Level
+	// Async queues entries and writes them from a background goroutine,
+	// so tracing calls never wait for the disk
+	Async bool
+	// BufferSize is the capacity of the async queue (default: 1024 entries)
+	BufferSize int
+	// DropOnFull drops entries when the async queue is full instead of blocking
+	DropOnFull bool
+	// Encoder selects the log file format (default: HTMLEncoder)
+	Encoder Encoder
+	// Sinks lists the destinations of the entries. When empty, entries go to
+	// stdout and to a trace.<ext> file written with Encoder.
+	Sinks []Sink
+	// DisableStdout turns off the default stdout sink
+	DisableStdout bool
+	// Enabled forces the log files on or off. With EnableAuto (default) they are
+	// written when TRACER_ENABLE is set to a true value, or while a marker file exists.
+	Enabled EnableMode
+	// EnableFiles are the names of the marker files
+	// (default: TraceEnable.txt, TraceIntegraEnable.txt and Trace.txt)
+	EnableFiles []string
+	// EnableDirs are the folders searched for the marker files (default: the working directory)
+	EnableDirs []string
+	// EnableCheckInterval is how long the enable check is cached (default: 1s)
+	EnableCheckInterval time.Duration
+	// Modules sets the levels of the module tracers, e.g. "poller=debug,http=warn,*=info".
+	// A level may also be "off" or "on", the latter keeping MinLevel. The entry of a
+	// module also applies to its nested modules, e.g. "poller" to "poller.tcp".
+	Modules string
+	// IncludeCaller records the file, line and function of the call that wrote each entry
+	IncludeCaller bool
+	// IncludePID adds the process ID to every entry
+	IncludePID bool
+	// IncludeGoroutineID adds the ID of the goroutine that wrote the entry, followed
+	// by its name set with SetGoroutineName or WithGoroutineName, if any. It looks the
+	// goroutine up with a stack trace on every entry.
+	IncludeGoroutineID bool
+	// IncludeGoroutineName adds the name set with SetGoroutineName or WithGoroutineName
+	// without the ID. It only looks the goroutine up once SetGoroutineName is in use.
+	IncludeGoroutineName bool
+	// SlowScope colors the exit line of the scopes that take longer in orange (default: off)
+	SlowScope time.Duration
+}
+
+// Entry is a single trace log entry
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Color   string
+	UserID  string
+	Module  string
+	Message string
+	// HTML reports whether Message is trusted markup that must not be escaped.
+	// Text returns the message without the markup, for the other formats.
+	HTML   bool
+	Fields []Field
+	// Caller is the location of the call that wrote the entry, when IncludeCaller is set
+	Caller Caller
+	// PID, GoroutineID and Goroutine, the name set with SetGoroutineName, identify
+	// the writer of the entry. PID and GoroutineID are zero unless included.
+	PID         int
+	GoroutineID uint64
+	Goroutine   string
+	// Depth is the number of scopes the entry is nested in
+	Depth int
+	// TraceID and SpanID are the hex IDs of the span the entry was traced in, if any
+	TraceID string
+	SpanID  string
 }
 
 var defaultConfig = Config{
@@ -93,303 +202,466 @@ var defaultConfig = Config{
 	UserID:         "",
 	MaxSize:        logMaxSize,
 	MaxFiles:       maxFiles,
+	BufferSize:     asyncBufferSize,
+	Encoder:        HTMLEncoder{},
+}
+
+// Tracer writes trace entries to its own "Trace <ExecutableName>" folder.
+// Each Tracer has its own configuration, so several subsystems of one process
+// can keep separate logs with their own size limits and user ID.
+type Tracer struct {
+	*core
+	fields     []Field
+	module     string
+	callerSkip int
+	// userID, goroutine, depth and span are the values of a context, see WithContext
+	userID    string
+	goroutine string
+	depth     int
+	span      SpanContext
+}
+
+// core is the state shared by a Tracer and the child tracers derived from it
+type core struct {
+	mutex      sync.Mutex
+	writeMutex sync.Mutex
+	config     Config
+	async      *asyncWriter
+	dropped    atomic.Uint64
+	logFiles   map[string]*LogFile
+	background sync.WaitGroup
+	// cleanupMutex serializes the background compression and retention passes
+	cleanupMutex sync.Mutex
+	// sweep enforces MaxAge between rotations while log files are open, every
+	// sweepInterval (default: retentionInterval)
+	sweep         *retentionSweep
+	sweepInterval time.Duration
+	// enable caches the enable check and the settings of the marker file
+	enable enableState
+	// watch is the config file applied on top of config, if any
+	watch *configWatch
+	// moduleOverrides are the module levels and switches set at runtime, and
+	// moduleRules is the parsed config.Modules
+	moduleOverrides map[string]moduleOverride
+	moduleSpec      string
+	moduleRules     moduleRules
+	// includeCaller mirrors config.IncludeCaller, checked before walking the stack
+	includeCaller atomic.Bool
+}
+
+// std is the Tracer used by the package-level functions
+var std = New(Config{})
+
+// New creates a Tracer with the given configuration.
+// Zero-valued fields take the package defaults.
+func New(cfg Config) *Tracer {
+	t := &Tracer{core: &core{config: defaultConfig}}
+	t.SetConfig(cfg)
+	return t
+}
+
+// Default returns the Tracer used by the package-level functions
+func Default() *Tracer {
+	return std
 }
 
 // SetConfig allows customization of the tracer configuration
-func SetConfig(cfg Config) {
-	globalMutex.Lock()
-	defer globalMutex.Unlock()
+func (t *Tracer) SetConfig(cfg Config) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 	if cfg.MaxSize > 0 {
-		defaultConfig.MaxSize = cfg.MaxSize
+		t.config.MaxSize = cfg.MaxSize
 	}
 	if cfg.MaxFiles > 0 {
-		defaultConfig.MaxFiles = cfg.MaxFiles
+		t.config.MaxFiles = cfg.MaxFiles
+	}
+	if cfg.Dir != "" {
+		t.config.Dir = cfg.Dir
+	}
+	if cfg.DirEnv != "" {
+		t.config.DirEnv = cfg.DirEnv
+	}
+	if cfg.UseStateDir {
+		t.config.UseStateDir = true
+	}
+	if cfg.FileName != "" {
+		t.config.FileName = cfg.FileName
+	}
+	if cfg.RotatedName != "" {
+		t.config.RotatedName = cfg.RotatedName
+	}
+	if cfg.Rotation != 0 {
+		t.config.Rotation = cfg.Rotation
+	}
+	if cfg.Compress {
+		t.config.Compress = true
+	}
+	if cfg.MaxAge > 0 {
+		t.config.MaxAge = cfg.MaxAge
+	}
+	if cfg.MaxTotalBytes > 0 {
+		t.config.MaxTotalBytes = cfg.MaxTotalBytes
+	}
+	if cfg.OnRemove != nil {
+		t.config.OnRemove = cfg.OnRemove
+	}
+	if cfg.MultiProcess {
+		t.config.MultiProcess = true
 	}
 	if cfg.ExecutableName != "" {
-		defaultConfig.ExecutableName = cfg.ExecutableName
+		t.config.ExecutableName = cfg.ExecutableName
 	}
 	if cfg.UserID != "" {
-		defaultConfig.UserID = cfg.UserID
+		t.config.UserID = cfg.UserID
+	}
+	if cfg.MinLevel > LevelDebug {
+		t.config.MinLevel = cfg.MinLevel
+	}
+	if cfg.BufferSize > 0 {
+		t.config.BufferSize = cfg.BufferSize
+	}
+	if cfg.DropOnFull {
+		t.config.DropOnFull = true
+	}
+	if cfg.Encoder != nil {
+		t.config.Encoder = cfg.Encoder
+	}
+	if cfg.Sinks != nil {
+		t.config.Sinks = cfg.Sinks
+	}
+	if cfg.DisableStdout {
+		t.config.DisableStdout = true
+	}
+	if cfg.Enabled != EnableAuto {
+		t.config.Enabled = cfg.Enabled
+	}
+	if cfg.EnableFiles != nil {
+		t.config.EnableFiles = cfg.EnableFiles
+	}
+	if cfg.EnableDirs != nil {
+		t.config.EnableDirs = cfg.EnableDirs
+	}
+	if cfg.EnableCheckInterval > 0 {
+		t.config.EnableCheckInterval = cfg.EnableCheckInterval
+	}
+	if cfg.Modules != "" {
+		if _, err := parseModules(cfg.Modules); err != nil {
+			fmt.Printf("Error parsing modules: %v\n", err)
+		} else {
+			t.config.Modules = cfg.Modules
+		}
+	}
+	// Look the enable setting up again with the new configuration
+	t.refreshEnabled()
+	if cfg.IncludeCaller {
+		t.config.IncludeCaller = true
+		t.includeCaller.Store(true)
+	}
+	if cfg.IncludePID {
+		t.config.IncludePID = true
+	}
+	if cfg.IncludeGoroutineID {
+		t.config.IncludeGoroutineID = true
+	}
+	if cfg.IncludeGoroutineName {
+		t.config.IncludeGoroutineName = true
+	}
+	if cfg.SlowScope > 0 {
+		t.config.SlowScope = cfg.SlowScope
+	}
+	if cfg.Async && t.async == nil {
+		t.config.Async = true
+		t.async = t.startAsync(t.config.BufferSize, t.config.DropOnFull)
 	}
 }
 
+// Enabled reports whether entries of the given level are written
+func (t *Tracer) Enabled(level Level) bool {
+	cfg := t.currentConfig()
+	minLevel, ok := t.minLevel(&cfg)
+	return ok && level >= minLevel
+}
+
+// SetMinLevel sets the minimum level of the entries that are written.
+// Unlike SetConfig it also accepts LevelDebug to log everything again.
+func (t *Tracer) SetMinLevel(level Level) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.config.MinLevel = level
+}
+
 // SetUserID sets the user ID that will appear in log entries
+func (t *Tracer) SetUserID(userID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.config.UserID = userID
+}
+
+// SetConfig allows customization of the default tracer configuration
+func SetConfig(cfg Config) {
+	std.SetConfig(cfg)
+}
+
+// SetUserID sets the user ID that will appear in log entries of the default tracer
 func SetUserID(userID string) {
-	globalMutex.Lock()
-	defer globalMutex.Unlock()
-	defaultConfig.UserID = userID
+	std.SetUserID(userID)
 }
 
-// NewLogFile creates a new LogFile instance
-func newLogFile(filename string, maxSize int64) *LogFile {
-	currentSize := int64(0)
-	if info, err := os.Stat(filename); err == nil {
-		currentSize = info.Size()
-	}
+// SetMinLevel sets the minimum level of the entries written by the default tracer
+func SetMinLevel(level Level) {
+	std.SetMinLevel(level)
+}
 
-	return &LogFile{
-		filename:    filename,
-		maxSize:     maxSize,
-		currentSize: currentSize,
-	}
+// Trace writes values to the trace log with white color (like fmt.Println)
+// Multiple arguments are separated by spaces.
+func (t *Tracer) Trace(a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintMessage(a...), "")
 }
 
-func (lf *LogFile) openFile() error {
-	lf.mutex.Lock()
-	defer lf.mutex.Unlock()
+// Tracef writes a formatted message to the trace log with white color (like fmt.Printf)
+func (t *Tracer) Tracef(format string, a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintfMessage(format, a...), "")
+}
 
-	if lf.file != nil {
-		return nil
-	}
+// TraceWithColor writes values to the trace log with a specified color (like fmt.Println)
+// Multiple arguments are separated by spaces.
+func (t *Tracer) TraceWithColor(color string, a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintMessage(a...), color)
+}
 
-	file, err := os.OpenFile(lf.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	lf.file = file
-	return nil
+// TraceWithColorf writes a formatted message to the trace log with a specified color (like fmt.Printf)
+func (t *Tracer) TraceWithColorf(color string, format string, a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintfMessage(format, a...), color)
 }
 
-func (lf *LogFile) closeFile() {
-	lf.mutex.Lock()
-	defer lf.mutex.Unlock()
+// Debug writes values to the trace log at debug level (like fmt.Println)
+func (t *Tracer) Debug(a ...any) {
+	t.traceWithColorInternal(LevelDebug, sprintMessage(a...), "")
+}
 
-	if lf.file != nil {
-		lf.file.Close()
-		lf.file = nil
-	}
+// Debugf writes a formatted message to the trace log at debug level (like fmt.Printf)
+func (t *Tracer) Debugf(format string, a ...any) {
+	t.traceWithColorInternal(LevelDebug, sprintfMessage(format, a...), "")
 }
 
-func (lf *LogFile) rotateFile() error {
-	lf.closeFile()
+// Info writes values to the trace log at info level (like fmt.Println)
+func (t *Tracer) Info(a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintMessage(a...), "")
+}
 
-	currentDate := time.Now().Format("2006-01-02_15_04_05")
-	dir := filepath.Dir(lf.filename)
-	base := filepath.Base(lf.filename)
-	newFilename := filepath.Join(dir, fmt.Sprintf("%s_%s", currentDate, base))
+// Infof writes a formatted message to the trace log at info level (like fmt.Printf)
+func (t *Tracer) Infof(format string, a ...any) {
+	t.traceWithColorInternal(LevelInfo, sprintfMessage(format, a...), "")
+}
 
-	if err := os.Rename(lf.filename, newFilename); err != nil {
-		return err
-	}
+// Warn writes values to the trace log at warning level (like fmt.Println)
+func (t *Tracer) Warn(a ...any) {
+	t.traceWithColorInternal(LevelWarn, sprintMessage(a...), "")
+}
 
-	lf.currentSize = 0
-	return lf.openFile()
+// Warnf writes a formatted message to the trace log at warning level (like fmt.Printf)
+func (t *Tracer) Warnf(format string, a ...any) {
+	t.traceWithColorInternal(LevelWarn, sprintfMessage(format, a...), "")
 }
 
-func (lf *LogFile) write(data string) error {
-	if lf.file == nil {
-		if err := lf.openFile(); err != nil {
-			return err
-		}
-	}
+// sprintln formats its arguments like fmt.Sprintln without the trailing newline
+func sprintln(a ...any) string {
+	message := fmt.Sprintln(a...)
+	// Remove the trailing newline added by Sprintln
+	return message[:len(message)-1]
+}
 
-	dataBytes := []byte(data)
-	dataLen := int64(len(dataBytes))
+// traceWithColorInternal is the internal implementation that writes a message to the trace log.
+// An empty or invalid color selects the default color of the level.
+func (t *Tracer) traceWithColorInternal(level Level, msg message, color string, fields ...Field) {
+	// Skip log, traceWithColorInternal and the exported method calling it
+	t.log(time.Now(), level, msg, color, fields, func() Caller { return t.caller(4) })
+}
 
-	if lf.currentSize+dataLen > lf.maxSize {
-		if err := lf.rotateFile(); err != nil {
-			return err
-		}
-	}
+// log builds an entry with the tracer fields and the given fields, and sends it to the sinks.
+// The caller is only looked up once the level and module checks let the entry through.
+func (t *Tracer) log(now time.Time, level Level, msg message, color string, fields []Field, caller func() Caller) {
+	t.mutex.Lock()
+	async := t.async
+	t.mutex.Unlock()
 
-	lf.mutex.Lock()
-	defer lf.mutex.Unlock()
+	cfg := t.currentConfig()
+	if minLevel, ok := t.minLevel(&cfg); !ok || level < minLevel {
+		return
+	}
 
-	n, err := lf.file.WriteString(data)
-	if err != nil {
-		return err
+	if !validColor(color) {
+		color = level.Color()
+	}
+	entry := &Entry{
+		Time:    now,
+		Level:   level,
+		Color:   color,
+		UserID:  cfg.UserID,
+		Module:  t.module,
+		Message: msg.text,
+		HTML:    msg.html,
+		Fields:  append(t.fields[:len(t.fields):len(t.fields)], fields...),
+		Caller:  caller(),
+		Depth:   t.depth,
+	}
+	if t.userID != "" {
+		entry.UserID = t.userID
+	}
+	setProcessColumns(entry, &cfg)
+	if t.goroutine != "" && (cfg.IncludeGoroutineID || cfg.IncludeGoroutineName) {
+		entry.Goroutine = t.goroutine
+	}
+	if t.span.IsValid() {
+		entry.TraceID, entry.SpanID = t.span.TraceID.String(), t.span.SpanID.String()
 	}
 
-	lf.currentSize += int64(n)
-	return nil
-}
+	if async != nil && async.enqueue(entry) {
+		return
+	}
 
-func (lf *LogFile) close() {
-	lf.closeFile()
-	lf.currentSize = 0
+	t.writeEntries([]*Entry{entry}, cfg)
 }
 
-func createHTMLLogFile(logFilename string) error {
-	file, err := os.Create(logFilename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+// ReportException reports a panic/exception with stack trace
+func (t *Tracer) ReportException(err interface{}) {
+	stackTrace := string(debug.Stack())
 
-	_, err = file.WriteString(htmlPageHeader)
-	return err
+	t.traceWithColorInternal(LevelError, sprintfMessage("Bypassing exception (%v)", err), "")
+	t.traceWithColorInternal(LevelError, sprintfMessage("**** Exception: %s%s%s", RawHTML("<code>"), stackTrace, RawHTML("</code>")), "")
 }
 
-func getLogFiles(folderName string) ([]string, error) {
-	pattern := filepath.Join(folderName, "*.html")
-	files, err := filepath.Glob(pattern)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sort by creation time
-	sort.Slice(files, func(i, j int) bool {
-		info1, err1 := os.Stat(files[i])
-		info2, err2 := os.Stat(files[j])
-		if err1 != nil || err2 != nil {
-			return false
-		}
-		return info1.ModTime().Before(info2.ModTime())
-	})
+// Error writes an error message to the trace log in red (like fmt.Println)
+// Multiple arguments are separated by spaces and prefixed with "**"
+func (t *Tracer) Error(a ...any) {
+	t.traceWithColorInternal(LevelError, sprintMessage(a...).prefix("** "), "")
+}
 
-	return files, nil
+// Errorf writes a formatted error message to the trace log in red (like fmt.Printf)
+// The message is prefixed with "**"
+func (t *Tracer) Errorf(format string, a ...any) {
+	t.traceWithColorInternal(LevelError, sprintfMessage("** "+format, a...), "")
 }
 
-func removeOldestLogFile(folderName string) error {
-	logFiles, err := getLogFiles(folderName)
-	if err != nil {
-		return err
-	}
+// Fatal writes values to the trace log at fatal level, prefixed with "**",
+// and then calls os.Exit(1) like log.Fatal
+func (t *Tracer) Fatal(a ...any) {
+	t.traceWithColorInternal(LevelFatal, sprintMessage(a...).prefix("** "), "")
+	t.Flush()
+	os.Exit(1)
+}
 
-	if len(logFiles) >= defaultConfig.MaxFiles {
-		oldestFile := logFiles[0]
-		return os.Remove(oldestFile)
-	}
+// Fatalf writes a formatted message to the trace log at fatal level, prefixed with "**",
+// and then calls os.Exit(1) like log.Fatalf
+func (t *Tracer) Fatalf(format string, a ...any) {
+	t.traceWithColorInternal(LevelFatal, sprintfMessage("** "+format, a...), "")
+	t.Flush()
+	os.Exit(1)
+}
 
-	return nil
+// TraceSessionError writes a session error message to the trace log in LightSalmon color (like fmt.Println)
+// Multiple arguments are separated by spaces and prefixed with "**"
+func (t *Tracer) TraceSessionError(a ...any) {
+	t.traceWithColorInternal(LevelError, sprintMessage(a...).prefix("** "), "LightSalmon")
 }
 
-func isTraceEnabled() bool {
-	enableFiles := []string{"TraceEnable.txt", "TraceIntegraEnable.txt", "Trace.txt"}
-	for _, file := range enableFiles {
-		if _, err := os.Stat(file); err == nil {
-			return true
-		}
+// RecoverPanic should be used with defer to catch panics and log them
+func (t *Tracer) RecoverPanic() {
+	if r := recover(); r != nil {
+		// Report the function that panicked rather than RecoverPanic
+		t.AddCallerSkip(1).ReportException(r)
 	}
-	return false
 }
 
-// Trace writes values to the trace log with white color (like fmt.Println)
+// Trace writes values to the default trace log with white color (like fmt.Println)
 // Multiple arguments are separated by spaces.
 func Trace(a ...any) {
-	message := fmt.Sprintln(a...)
-	// Remove the trailing newline added by Sprintln
-	message = message[:len(message)-1]
-	traceWithColorInternal(message, "white")
+	stdCaller.Trace(a...)
 }
 
-// Tracef writes a formatted message to the trace log with white color (like fmt.Printf)
+// Tracef writes a formatted message to the default trace log with white color (like fmt.Printf)
 func Tracef(format string, a ...any) {
-	traceWithColorInternal(fmt.Sprintf(format, a...), "white")
+	stdCaller.Tracef(format, a...)
 }
 
-// TraceWithColor writes values to the trace log with a specified color (like fmt.Println)
+// TraceWithColor writes values to the default trace log with a specified color (like fmt.Println)
 // Multiple arguments are separated by spaces.
 func TraceWithColor(color string, a ...any) {
-	message := fmt.Sprintln(a...)
-	// Remove the trailing newline added by Sprintln
-	message = message[:len(message)-1]
-	traceWithColorInternal(message, color)
+	stdCaller.TraceWithColor(color, a...)
 }
 
-// TraceWithColorf writes a formatted message to the trace log with a specified color (like fmt.Printf)
+// TraceWithColorf writes a formatted message to the default trace log with a specified color (like fmt.Printf)
 func TraceWithColorf(color string, format string, a ...any) {
-	traceWithColorInternal(fmt.Sprintf(format, a...), color)
+	stdCaller.TraceWithColorf(color, format, a...)
 }
 
-// traceWithColorInternal is the internal implementation that writes a message to the trace log
-func traceWithColorInternal(message, color string) {
-	fmt.Println(message)
-
-	if !isTraceEnabled() {
-		return
-	}
-
-	globalMutex.Lock()
-	executableName := defaultConfig.ExecutableName
-	userID := defaultConfig.UserID
-	globalMutex.Unlock()
-
-	folderName := "Trace " + executableName
-
-	// Create folder if it doesn't exist
-	if err := os.MkdirAll(folderName, 0755); err != nil {
-		fmt.Printf("Error creating folder: %v\n", err)
-		return
-	}
-
-	logFilename := filepath.Join(folderName, "trace.html")
-
-	// Create HTML log file if it doesn't exist
-	if _, err := os.Stat(logFilename); os.IsNotExist(err) {
-		if err := createHTMLLogFile(logFilename); err != nil {
-			fmt.Printf("Error creating log file: %v\n", err)
-			return
-		}
-	}
-
-	// Remove oldest log file if needed
-	if err := removeOldestLogFile(folderName); err != nil {
-		fmt.Printf("Error removing old log files: %v\n", err)
-	}
-
-	logFile := newLogFile(logFilename, defaultConfig.MaxSize)
+// Debug writes values to the default trace log at debug level (like fmt.Println)
+func Debug(a ...any) {
+	stdCaller.Debug(a...)
+}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	userIDPart := ""
-	if userID != "" {
-		userIDPart = userID + " - "
-	}
-	logEntry := fmt.Sprintf("\n<br></font><font color=\"%s\">%s - %s%s", color, timestamp, userIDPart, message)
+// Debugf writes a formatted message to the default trace log at debug level (like fmt.Printf)
+func Debugf(format string, a ...any) {
+	stdCaller.Debugf(format, a...)
+}
 
-	if err := logFile.write(logEntry); err != nil {
-		logFile.close()
-		currentDate := time.Now().Format("2006-01-02_15_04_05")
-		newFilename := filepath.Join(folderName, fmt.Sprintf("%s - trace.html", currentDate))
+// Info writes values to the default trace log at info level (like fmt.Println)
+func Info(a ...any) {
+	stdCaller.Info(a...)
+}
 
-		if err := os.Rename(logFilename, newFilename); err != nil {
-			fmt.Printf("Error renaming log file: %v\n", err)
-			return
-		}
+// Infof writes a formatted message to the default trace log at info level (like fmt.Printf)
+func Infof(format string, a ...any) {
+	stdCaller.Infof(format, a...)
+}
 
-		if err := createHTMLLogFile(logFilename); err != nil {
-			fmt.Printf("Error creating new log file: %v\n", err)
-			return
-		}
+// Warn writes values to the default trace log at warning level (like fmt.Println)
+func Warn(a ...any) {
+	stdCaller.Warn(a...)
+}
 
-		logFile = newLogFile(logFilename, defaultConfig.MaxSize)
-		logFile.write(logEntry)
-	}
+// Warnf writes a formatted message to the default trace log at warning level (like fmt.Printf)
+func Warnf(format string, a ...any) {
+	stdCaller.Warnf(format, a...)
 }
 
-// ReportException reports a panic/exception with stack trace
+// ReportException reports a panic/exception with stack trace to the default trace log
 func ReportException(err interface{}) {
-	stackTrace := string(debug.Stack())
-
-	// Clean up stack trace for HTML
-	stackTrace = strings.ReplaceAll(stackTrace, "<", "&lt;")
-	stackTrace = strings.ReplaceAll(stackTrace, ">", "&gt;")
-
-	traceWithColorInternal(fmt.Sprintf("Bypassing exception (%v)", err), "red")
-	traceWithColorInternal(fmt.Sprintf("**** Exception: <code>%s</code>", stackTrace), "red")
+	stdCaller.ReportException(err)
 }
 
-// Error writes an error message to the trace log in red (like fmt.Println)
+// Error writes an error message to the default trace log in red (like fmt.Println)
 // Multiple arguments are separated by spaces and prefixed with "**"
 func Error(a ...any) {
-	message := fmt.Sprintln(a...)
-	// Remove the trailing newline added by Sprintln
-	message = message[:len(message)-1]
-	traceWithColorInternal(fmt.Sprintf("** %s", message), "red")
+	stdCaller.Error(a...)
 }
 
-// TraceSessionError writes a session error message to the trace log in LightSalmon color (like fmt.Println)
+// Errorf writes a formatted error message to the default trace log in red (like fmt.Printf)
+// The message is prefixed with "**"
+func Errorf(format string, a ...any) {
+	stdCaller.Errorf(format, a...)
+}
+
+// Fatal writes values to the default trace log at fatal level and then calls os.Exit(1)
+func Fatal(a ...any) {
+	stdCaller.Fatal(a...)
+}
+
+// Fatalf writes a formatted message to the default trace log at fatal level and then calls os.Exit(1)
+func Fatalf(format string, a ...any) {
+	stdCaller.Fatalf(format, a...)
+}
+
+// TraceSessionError writes a session error message to the default trace log in LightSalmon color (like fmt.Println)
 // Multiple arguments are separated by spaces and prefixed with "**"
 func TraceSessionError(a ...any) {
-	message := fmt.Sprintln(a...)
-	// Remove the trailing newline added by Sprintln
-	message = message[:len(message)-1]
-	traceWithColorInternal(fmt.Sprintf("** %s", message), "LightSalmon")
+	stdCaller.TraceSessionError(a...)
 }
 
-// RecoverPanic should be used with defer to catch panics and log them
+// RecoverPanic should be used with defer to catch panics and log them to the default trace log
 func RecoverPanic() {
 	if r := recover(); r != nil {
-		ReportException(r)
+		// Report the function that panicked rather than RecoverPanic
+		stdCaller.ReportException(r)
 	}
 }
@@ -18,12 +18,12 @@ func TestSetConfig(t *testing.T) {
 
 	SetConfig(cfg)
 
-	if defaultConfig.ExecutableName != "TestApp" {
-		t.Errorf("Expected ExecutableName to be 'TestApp', got '%s'", defaultConfig.ExecutableName)
+	if std.config.ExecutableName != "TestApp" {
+		t.Errorf("Expected ExecutableName to be 'TestApp', got '%s'", std.config.ExecutableName)
 	}
 
-	if defaultConfig.UserID != "TestUser" {
-		t.Errorf("Expected UserID to be 'TestUser', got '%s'", defaultConfig.UserID)
+	if std.config.UserID != "TestUser" {
+		t.Errorf("Expected UserID to be 'TestUser', got '%s'", std.config.UserID)
 	}
 }
 
@@ -31,8 +31,8 @@ func TestSetConfig(t *testing.T) {
 func TestSetUserID(t *testing.T) {
 	SetUserID("User123")
 
-	if defaultConfig.UserID != "User123" {
-		t.Errorf("Expected UserID to be 'User123', got '%s'", defaultConfig.UserID)
+	if std.config.UserID != "User123" {
+		t.Errorf("Expected UserID to be 'User123', got '%s'", std.config.UserID)
 	}
 }
 
@@ -209,7 +209,7 @@ func TestIsTraceEnabled(t *testing.T) {
 	os.Remove("TraceIntegraEnable.txt")
 	os.Remove("Trace.txt")
 
-	if isTraceEnabled() {
+	if isTraceEnabled(&defaultConfig) {
 		t.Error("Expected tracing to be disabled")
 	}
 
@@ -219,7 +219,7 @@ func TestIsTraceEnabled(t *testing.T) {
 	}
 	defer os.Remove("TraceEnable.txt")
 
-	if !isTraceEnabled() {
+	if !isTraceEnabled(&defaultConfig) {
 		t.Error("Expected tracing to be enabled")
 	}
 }
@@ -283,6 +283,46 @@ func TestTraceMultipleArgs(t *testing.T) {
 	}
 }
 
+// TestNewTracerSeparateFolders verifies that each Tracer writes to its own folder
+func TestNewTracerSeparateFolders(t *testing.T) {
+	enableFile := "TraceEnable.txt"
+	if err := os.WriteFile(enableFile, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to create enable file: %v", err)
+	}
+	defer os.Remove(enableFile)
+
+	poller := New(Config{ExecutableName: "TestPoller", UserID: "Poller"})
+	api := New(Config{ExecutableName: "TestAPI", UserID: "API"})
+	defer os.RemoveAll("Trace TestPoller")
+	defer os.RemoveAll("Trace TestAPI")
+
+	poller.Trace("Polling device", 2)
+	api.Tracef("GET %s", "/status")
+
+	content, err := os.ReadFile(filepath.Join("Trace TestPoller", "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read poller log file: %v", err)
+	}
+	if !contains(string(content), "Poller - Polling device 2") {
+		t.Error("Expected poller log to contain its own entry")
+	}
+	if contains(string(content), "/status") {
+		t.Error("Expected poller log not to contain the API entry")
+	}
+
+	content, err = os.ReadFile(filepath.Join("Trace TestAPI", "trace.html"))
+	if err != nil {
+		t.Fatalf("Failed to read API log file: %v", err)
+	}
+	if !contains(string(content), "API - GET /status") {
+		t.Error("Expected API log to contain its own entry")
+	}
+
+	if Default().config.ExecutableName == "TestPoller" {
+		t.Error("Expected New not to change the default tracer")
+	}
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) >= len(substr) && (s[0:len(substr)] == substr || contains(s[1:], substr)))
@@ -0,0 +1,224 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// configWatchInterval is how often a watched config file is checked for changes
+const configWatchInterval = time.Second
+
+// configWatch is a config file whose settings are applied on top of the Config
+type configWatch struct {
+	path     string
+	settings fileSettings
+	modTime  time.Time
+	size     int64
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// loadConfigFile reads the settings of a config file. Files ending in .json hold
+// a JSON object, any other file holds key=value or key: value lines.
+func loadConfigFile(path string) (fileSettings, os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var settings fileSettings
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		settings, err = parseJSONSettings(data)
+	} else {
+		settings, err = parseSettings(data)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, info, nil
+}
+
+// WatchConfig applies the settings of the config file at path on top of the
+// Config, and applies them again whenever the file changes. Settings removed from
+// the file go back to the Config value. A file with an invalid setting is not
+// applied at all. WatchConfig replaces the file watched before, if any, and
+// returns a function that stops watching.
+func (t *Tracer) WatchConfig(path string) (func(), error) {
+	settings, info, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watch := &configWatch{
+		path:     path,
+		settings: settings,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	t.stopWatching()
+	t.mutex.Lock()
+	t.watch = watch
+	t.mutex.Unlock()
+	t.settingsChanged()
+
+	go t.runWatch(watch)
+	return func() { watch.close() }, nil
+}
+
+func (t *Tracer) runWatch(watch *configWatch) {
+	defer close(watch.done)
+
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-watch.stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(watch.path)
+			if err != nil {
+				continue
+			}
+			t.mutex.Lock()
+			changed := !info.ModTime().Equal(watch.modTime) || info.Size() != watch.size
+			t.mutex.Unlock()
+			if !changed {
+				continue
+			}
+			if err := t.ReloadConfig(); err != nil {
+				fmt.Printf("Error reloading tracer config: %v\n", err)
+			}
+		}
+	}
+}
+
+// close stops watching the config file. Its settings stay applied.
+func (w *configWatch) close() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+}
+
+// stopWatching stops watching the current config file, if any
+func (c *core) stopWatching() {
+	c.mutex.Lock()
+	watch := c.watch
+	c.mutex.Unlock()
+
+	if watch != nil {
+		watch.close()
+	}
+}
+
+// ReloadConfig reads the watched config file again and applies its settings in one
+// step, then reopens the log file. Entries traced meanwhile are written with either
+// the old or the new settings, never lost. Without a watched file it does nothing.
+func (t *Tracer) ReloadConfig() error {
+	t.mutex.Lock()
+	watch := t.watch
+	t.mutex.Unlock()
+
+	if watch == nil {
+		return nil
+	}
+
+	settings, info, err := loadConfigFile(watch.path)
+	if err != nil {
+		// Keep the modification time, so a broken file is reported once
+		if info, statErr := os.Stat(watch.path); statErr == nil {
+			t.mutex.Lock()
+			watch.modTime, watch.size = info.ModTime(), info.Size()
+			t.mutex.Unlock()
+		}
+		return err
+	}
+
+	t.mutex.Lock()
+	watch.settings = settings
+	watch.modTime, watch.size = info.ModTime(), info.Size()
+	t.mutex.Unlock()
+
+	t.settingsChanged()
+	return nil
+}
+
+// settingsChanged picks up changed settings: the enable check is done again, and
+// the log files are reopened under their possibly new names and limits
+func (t *Tracer) settingsChanged() {
+	t.refreshEnabled()
+	t.Reopen()
+}
+
+// Reopen closes the log files, which are opened again by the next write. It lets
+// external tools such as logrotate move the files away.
+func (t *Tracer) Reopen() {
+	t.closeLogFile()
+}
+
+// HandleSIGHUP reloads the watched config file and reopens the log files whenever
+// the process receives SIGHUP. It returns a function that stops handling the signal.
+// On platforms without SIGHUP it does nothing.
+func (t *Tracer) HandleSIGHUP() func() {
+	signals := make(chan os.Signal, 1)
+	notifyReload(signals)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-signals:
+				if err := t.ReloadConfig(); err != nil {
+					fmt.Printf("Error reloading tracer config: %v\n", err)
+				}
+				// Also when no config file is watched, e.g. for logrotate
+				t.Reopen()
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(stop)
+		<-done
+	}
+}
+
+// WatchConfig applies the settings of a config file to the default tracer and
+// reloads them whenever the file changes
+func WatchConfig(path string) (func(), error) {
+	return std.WatchConfig(path)
+}
+
+// ReloadConfig reads the config file watched by the default tracer again
+func ReloadConfig() error {
+	return std.ReloadConfig()
+}
+
+// Reopen closes the log files of the default tracer, which are opened again by the next write
+func Reopen() {
+	std.Reopen()
+}
+
+// HandleSIGHUP reloads the config and reopens the log files of the default tracer on SIGHUP
+func HandleSIGHUP() func() {
+	return std.HandleSIGHUP()
+}
@@ -0,0 +1,86 @@
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatchConfig verifies that config file settings are applied and reloaded
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tracer.ini")
+	if err := os.WriteFile(path, []byte("[tracer]\nmax_files = 3\nlevel: warn\nrotation=daily|size\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	tr := New(Config{ExecutableName: "TestWatch", MaxFiles: 10, EnableDirs: []string{dir}})
+	defer tr.Close()
+
+	stop, err := tr.WatchConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to watch config: %v", err)
+	}
+	defer stop()
+
+	cfg := tr.currentConfig()
+	if cfg.MaxFiles != 3 || cfg.MinLevel != LevelWarn || cfg.Rotation != RotateDaily|RotateBySize {
+		t.Errorf("Expected 3/warn/daily|size, got %d/%v/%v", cfg.MaxFiles, cfg.MinLevel, cfg.Rotation)
+	}
+
+	// An invalid file is rejected as a whole
+	if err := os.WriteFile(path, []byte("max_files = 5\nlevel = loud\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := tr.ReloadConfig(); err == nil {
+		t.Error("Expected an error for the invalid config file")
+	}
+	if cfg := tr.currentConfig(); cfg.MaxFiles != 3 {
+		t.Errorf("Expected the previous settings to stay applied, got MaxFiles %d", cfg.MaxFiles)
+	}
+
+	// Removed settings go back to the Config value
+	if err := os.WriteFile(path, []byte("level = debug\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := tr.ReloadConfig(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	cfg = tr.currentConfig()
+	if cfg.MaxFiles != 10 || cfg.MinLevel != LevelDebug {
+		t.Errorf("Expected 10/debug, got %d/%v", cfg.MaxFiles, cfg.MinLevel)
+	}
+}
+
+// TestWatchConfigPicksUpChanges verifies that the watch notices an edited file
+func TestWatchConfigPicksUpChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tracer.json")
+	if err := os.WriteFile(path, []byte(`{"enabled": "off", "max_size": "2MB"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	tr := New(Config{ExecutableName: "TestWatchJSON"})
+	defer tr.Close()
+
+	if _, err := tr.WatchConfig(path); err != nil {
+		t.Fatalf("Failed to watch config: %v", err)
+	}
+	if cfg := tr.currentConfig(); cfg.Enabled != EnableOff || cfg.MaxSize != 2_000_000 {
+		t.Errorf("Expected off/2MB, got %v/%d", cfg.Enabled, cfg.MaxSize)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"enabled": true, "max_size": 3000000, "compress": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * configWatchInterval)
+	for time.Now().Before(deadline) {
+		if cfg := tr.currentConfig(); cfg.Enabled == EnableOn && cfg.MaxSize == 3_000_000 && cfg.Compress {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("Expected the edited config file to be applied")
+}